@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/andersfylling/go-sortnet/sortnet/cert"
+)
+
+func runCheck(args []string) error {
+	flags := flag.NewFlagSet("check", errorHandling)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected a single certificate file")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	result, err := cert.Check(file)
+	if err != nil {
+		return err
+	}
+
+	unit := "comparators"
+	if result.Mode == cert.DepthMode {
+		unit = "layers"
+	}
+	fmt.Fprintf(stdout, "valid certificate: a sorting network of %d channels needs at least %d %s, %d networks found\n",
+		result.Channels, result.Bound, unit, len(result.Sorting))
+	fmt.Fprintln(stdout, result.Sorting[0])
+	return nil
+}
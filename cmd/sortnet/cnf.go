@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/sat"
+)
+
+func runCNF(args []string) error {
+	flags := flag.NewFlagSet("cnf", errorHandling)
+	channels := flags.Int("channels", 3, "number of channels in the network")
+	comparators := flags.Int("comparators", 0, "number of comparators after the prefix")
+	depth := flags.Int("depth", 0, "number of layers after the prefix")
+	prefix := flags.String("prefix", "", "file holding the network every solution starts with")
+	model := flags.String("model", "", "decode the network from the output of a SAT solver instead of writing the formula")
+	solve := flags.Bool("solve", false, "solve the formula with the built-in solver instead of writing it")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	problem := sat.Problem{
+		Channels:    *channels,
+		Comparators: *comparators,
+		Depth:       *depth,
+	}
+
+	var err error
+	if *prefix != "" {
+		if problem.Prefix, err = readNetwork([]string{*prefix}, 0); err != nil {
+			return err
+		}
+	}
+
+	formula, err := sat.Encode(problem)
+	if err != nil {
+		return err
+	}
+
+	var network *sortnet.ComparatorNetwork
+	switch {
+	case *solve:
+		if network, err = formula.Solve(context.Background()); err != nil {
+			return err
+		}
+	case *model != "":
+		if network, err = decodeModel(formula, *model); err != nil {
+			return err
+		}
+	default:
+		return formula.WriteDIMACS(stdout)
+	}
+
+	ok, counterexample, err := isSortingNetwork(network, *channels)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("decoded network does not sort %b", counterexample.Input)
+	}
+
+	text, err := network.MarshalLayeredText()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, string(text))
+	return nil
+}
+
+func decodeModel(formula *sat.Formula, path string) (*sortnet.ComparatorNetwork, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	assignment, err := sat.ReadModel(file, formula.Variables)
+	if err != nil {
+		return nil, err
+	}
+	return formula.Decode(assignment)
+}
@@ -0,0 +1,73 @@
+// Command sortnet searches for, verifies and renders sorting networks.
+//
+//	sortnet search -channels 5
+//	sortnet verify network.txt
+//	sortnet render network.txt
+//	sortnet stats network.txt
+//	sortnet cnf -channels 8 -depth 6 > formula.cnf
+//	sortnet worker -connect unix:/tmp/sortnet.sock
+//	sortnet check search.cert
+//
+// Networks are read and written in the common literature notation, eg. [(0,1),(2,3),(0,2),(1,3),(1,2)].
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// stdout receives the output of the commands and errorHandling decides what malformed flags do, the tests replace both.
+var (
+	stdout        io.Writer = os.Stdout
+	errorHandling           = flag.ExitOnError
+)
+
+type command struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+var commands = []command{
+	{"search", "search for a sorting network of minimal size or depth", runSearch},
+	{"verify", "check that a network sorts every input", runVerify},
+	{"render", "draw a network", runRender},
+	{"stats", "print statistics about a network", runStats},
+	{"cnf", "encode the existence of a sorting network for a SAT solver", runCNF},
+	{"worker", "prune for a search started with -coordinator", runWorker},
+	{"check", "verify a proof certificate written by search -certificate", runCheck},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: sortnet <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.description)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != os.Args[1] {
+			continue
+		}
+
+		if err := cmd.run(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "sortnet %s: %s\n", cmd.name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "sortnet: unknown command %q\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func init() {
+	errorHandling = flag.ContinueOnError
+}
+
+// capture runs a command and returns what it wrote to stdout.
+func capture(t *testing.T, run func(args []string) error, args ...string) (string, error) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	stdout = &buf
+	defer func() { stdout = os.Stdout }()
+
+	err := run(args)
+	return buf.String(), err
+}
+
+func TestFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"-channels", "four"},
+		{"-unknown"},
+		{"-mode", "width"},
+		{"-set", "sparse"},
+		{"-permutations", "none"},
+		{"-pruning", "lazy"},
+	} {
+		if _, err := capture(t, runSearch, args...); err == nil {
+			t.Errorf("expected search %v to be rejected", args)
+		}
+	}
+
+	if _, err := lookup("output set", outputSets, "sparse"); err == nil || !strings.Contains(err.Error(), names(outputSets)) {
+		t.Errorf("expected the error to list the output sets, got %v", err)
+	}
+	if _, err := capture(t, runVerify, "a.txt", "b.txt"); err == nil {
+		t.Error("expected verify to reject more than one network file")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "network.txt")
+	output, err := capture(t, runSearch, "-channels", "4", "-workers", "2", "-out", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "found 1 sorting network(s) with 5 comparators") {
+		t.Errorf("expected a network of 5 comparators, got:\n%s", output)
+	}
+
+	output, err = capture(t, runVerify, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "sorting network with 4 channels and 5 comparators\n"; output != expected {
+		t.Errorf("expected %q, got %q", expected, output)
+	}
+
+	if _, err = capture(t, runVerify, "-channels", "3", path); err == nil {
+		t.Error("expected verify to reject a network wider than the given channels")
+	}
+}
+
+func TestReadNetwork(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "network.txt")
+	if err := os.WriteFile(path, []byte("[(0,1),(2,3),(0,2),(1,3),(1,2)]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	network, err := readNetwork([]string{path}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network.Channels() != 4 || network.Len() != 5 {
+		t.Errorf("expected 4 channels and 5 comparators, got %d and %d", network.Channels(), network.Len())
+	}
+
+	if network, err = readNetwork([]string{path}, 6); err != nil {
+		t.Fatal(err)
+	}
+	if network.Channels() != 6 {
+		t.Errorf("expected the network to be widened to 6 channels, got %d", network.Channels())
+	}
+	if ok, _, err := isSortingNetwork(network, network.Channels()); err != nil || ok {
+		t.Errorf("expected the widened network to not sort, got %t, %v", ok, err)
+	}
+}
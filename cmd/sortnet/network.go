@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+// readNetwork reads a network from the file given as the only positional argument, or stdin when there is none.
+// The channel count is inferred from the network unless channels is above 0.
+func readNetwork(args []string, channels int) (*sortnet.ComparatorNetwork, error) {
+	var input io.Reader = os.Stdin
+	switch {
+	case len(args) > 1:
+		return nil, fmt.Errorf("expected a single network file, got %d arguments", len(args))
+	case len(args) == 1 && args[0] != "-":
+		file, err := os.Open(args[0])
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+
+	network, err := sortnet.ParseComparatorNetwork(string(data))
+	if err != nil || channels <= 0 {
+		return network, err
+	}
+
+	return sortnet.NewComparatorNetwork(channels, network.Comparators()...)
+}
+
+// isSortingNetwork validates the channels before verifying the network, as sortnet.IsSortingNetwork panics on channels
+// it does not support.
+func isSortingNetwork(network *sortnet.ComparatorNetwork, channels int) (bool, *sortnet.Counterexample, error) {
+	if channels < 0 || channels > sortnet.MaxChannels {
+		return false, nil, fmt.Errorf("channels must be in the range [0, %d], got %d", sortnet.MaxChannels, channels)
+	}
+	if network.Channels() > channels {
+		return false, nil, fmt.Errorf("network has %d channels, but only %d are checked", network.Channels(), channels)
+	}
+
+	ok, counterexample := sortnet.IsSortingNetwork(network, channels)
+	return ok, counterexample, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/outputset"
+	"github.com/andersfylling/go-sortnet/sortnet/search"
+)
+
+var outputSets = map[string]outputset.NewSet{
+	"partitioned-ordered":   outputset.NewPartitionedOrdered,
+	"partitioned-unordered": outputset.NewPartitionedUnordered,
+	"unordered":             outputset.NewUnordered,
+	"warhol":                outputset.NewWarhol,
+	"dense":                 outputset.NewDense,
+}
+
+var permutationGenerators = sortnet.SubsetPermutationGenerators
+
+var searchModes = map[string]search.Mode{
+	"size":  search.SizeMode,
+	"depth": search.DepthMode,
+}
+
+var pruningStrategies = map[string]search.PruningStrategy{
+	"parallel": search.ParallelPruning,
+	"serial":   search.SerialPruning,
+}
+
+func names[T any](options map[string]T) string {
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return strings.Join(keys, ", ")
+}
+
+func lookup[T any](kind string, options map[string]T, name string) (T, error) {
+	option, ok := options[name]
+	if !ok {
+		return option, fmt.Errorf("unknown %s %q, expected one of: %s", kind, name, names(options))
+	}
+
+	return option, nil
+}
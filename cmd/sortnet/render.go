@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+func runRender(args []string) error {
+	flags := flag.NewFlagSet("render", errorHandling)
+	format := flags.String("format", "ascii", "output format: ascii, text, layered or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	network, err := readNetwork(flags.Args(), 0)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch *format {
+	case "ascii":
+		data = []byte(strings.TrimSuffix(network.String(), "\n"))
+	case "text":
+		data, err = network.MarshalFlatText()
+	case "layered":
+		data, err = network.MarshalLayeredText()
+	case "json":
+		data, err = json.Marshal(network)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, string(data))
+	return nil
+}
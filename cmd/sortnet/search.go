@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/distributed"
+	"github.com/andersfylling/go-sortnet/sortnet/known"
+	"github.com/andersfylling/go-sortnet/sortnet/search"
+)
+
+func runSearch(args []string) error {
+	flags := flag.NewFlagSet("search", errorHandling)
+	channels := flags.Int("channels", 3, "number of channels in the network")
+	mode := flags.String("mode", "size", "minimise the number of comparators or layers: "+names(searchModes))
+	allLayers := flags.Bool("all-layers", false, "derive every layer in depth mode, not only the maximal ones")
+	set := flags.String("set", "partitioned-ordered", "output set implementation: "+names(outputSets))
+	permutations := flags.String("permutations", "subset", "permutation generator: "+names(permutationGenerators))
+	pruning := flags.String("pruning", "parallel", "pruning strategy: "+names(pruningStrategies))
+	reflection := flags.Bool("reflection", false, "also prune by the reflection of every output set")
+	workers := flags.Int("workers", runtime.NumCPU(), "number of goroutines used for generating and pruning")
+	maxRounds := flags.Int("max-rounds", 0, "stop after the given number of rounds, 0 means no limit")
+	out := flags.String("out", "", "write the discovered network to the given file")
+	checkpoint := flags.String("checkpoint", "", "write the search state to the given file after every round")
+	checkpointEvery := flags.Int("checkpoint-every", 0, "also write the checkpoint every given number of pruning steps")
+	resume := flags.Bool("resume", false, "continue the search from the checkpoint")
+	certificate := flags.String("certificate", "", "write a proof certificate of the search to the given file")
+	coordinator := flags.String("coordinator", "", "prune with worker processes connecting to this address, eg. unix:/tmp/sortnet.sock or tcp::7000")
+	remoteWorkers := flags.Int("remote-workers", 1, "number of worker processes to wait for when coordinating")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	config := search.Config{
+		Channels:   *channels,
+		AllLayers:  *allLayers,
+		Reflection: *reflection,
+		Workers:    *workers,
+		MaxRounds:  *maxRounds,
+
+		CheckpointPath:  *checkpoint,
+		CheckpointEvery: *checkpointEvery,
+		Resume:          *resume,
+
+		OnRound: func(stats search.RoundStats) {
+			fmt.Fprintf(stdout, "round %d: generated %d, redundant %d, pruned %d, remaining %d (%s)\n",
+				stats.Round, stats.Generated, stats.Redundant, stats.Pruned, stats.Remaining, stats.Duration)
+		},
+	}
+
+	var err error
+	if config.Mode, err = lookup("search mode", searchModes, *mode); err != nil {
+		return err
+	}
+	if config.NewSet, err = lookup("output set", outputSets, *set); err != nil {
+		return err
+	}
+	if config.GeneratePermutations, err = lookup("permutation generator", permutationGenerators, *permutations); err != nil {
+		return err
+	}
+	if config.PruningStrategy, err = lookup("pruning strategy", pruningStrategies, *pruning); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *coordinator != "" {
+		c, err := distributed.Listen(*coordinator)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		c.Generator = *permutations
+
+		fmt.Fprintf(stdout, "waiting for %d worker(s) on %s\n", *remoteWorkers, c.Addr())
+		if err = c.Accept(ctx, *remoteWorkers); err != nil {
+			return err
+		}
+		config.Pruner = c
+	}
+
+	// the certificate is only emptied once the configuration is valid, as resuming continues it
+	var certificateFile *os.File
+	if *certificate != "" {
+		if certificateFile, err = os.OpenFile(*certificate, os.O_RDWR|os.O_CREATE, 0o644); err != nil {
+			return err
+		}
+		defer certificateFile.Close()
+		config.Certificate = certificateFile
+	}
+
+	engine, err := search.New(config)
+	if err != nil {
+		return err
+	}
+	if certificateFile != nil && !*resume {
+		if err = certificateFile.Truncate(0); err != nil {
+			return err
+		}
+	}
+
+	result, err := engine.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	if config.Mode == search.DepthMode {
+		fmt.Fprintf(stdout, "\nfound %d sorting network(s) of depth %d\n\n", len(result.Networks), result.Depth)
+	} else {
+		fmt.Fprintf(stdout, "\nfound %d sorting network(s) with %d comparators\n\n", len(result.Networks), result.Comparators)
+	}
+	fmt.Fprintln(stdout, result.Networks[0])
+
+	bound := "best known"
+	if config.Mode == search.DepthMode {
+		if best, ok := known.BestByDepth(*channels); ok {
+			if best.Proven {
+				bound = "proven optimal"
+			}
+			fmt.Fprintf(stdout, "\n%s depth for %d channels is %d layers\n", bound, *channels, best.Depth)
+		}
+	} else if best, ok := known.BestBySize(*channels); ok {
+		if best.Proven {
+			bound = "proven optimal"
+		}
+		fmt.Fprintf(stdout, "\n%s size for %d channels is %d comparators\n", bound, *channels, best.Size)
+	}
+
+	if *out == "" {
+		return nil
+	}
+
+	text, err := result.Networks[0].(*sortnet.ComparatorNetwork).MarshalText()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*out, append(text, '\n'), 0o644)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/outputset"
+)
+
+func runStats(args []string) error {
+	flags := flag.NewFlagSet("stats", errorHandling)
+	channels := flags.Int("channels", 0, "number of channels, defaults to the highest channel used by the network")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	network, err := readNetwork(flags.Args(), *channels)
+	if err != nil {
+		return err
+	}
+
+	md := sortnet.BitSlicedOutputSet(outputset.NewEmptyPartitionedOrdered(), network, network.Channels()).Metadata()
+	sorting, _, err := isSortingNetwork(network, network.Channels())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "channels:        %d\n", network.Channels())
+	fmt.Fprintf(stdout, "comparators:     %d\n", network.Len())
+	fmt.Fprintf(stdout, "depth:           %d\n", network.Depth())
+	fmt.Fprintf(stdout, "output set size: %d\n", md.Size)
+	fmt.Fprintf(stdout, "partition sizes: %v\n", md.PartitionSizes)
+	fmt.Fprintf(stdout, "sorting:         %t\n", sorting)
+	return nil
+}
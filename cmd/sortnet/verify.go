@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+func runVerify(args []string) error {
+	flags := flag.NewFlagSet("verify", errorHandling)
+	channels := flags.Int("channels", 0, "number of channels, defaults to the highest channel used by the network")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	network, err := readNetwork(flags.Args(), *channels)
+	if err != nil {
+		return err
+	}
+
+	ok, counterexample, err := isSortingNetwork(network, network.Channels())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("not a sorting network: input %0*b produces %0*b",
+			network.Channels(), counterexample.Input, network.Channels(), counterexample.Output)
+	}
+
+	fmt.Fprintf(stdout, "sorting network with %d channels and %d comparators\n", network.Channels(), network.Len())
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"runtime"
+
+	"github.com/andersfylling/go-sortnet/sortnet/distributed"
+)
+
+func runWorker(args []string) error {
+	flags := flag.NewFlagSet("worker", errorHandling)
+	connect := flags.String("connect", "", "address of the coordinator, eg. unix:/tmp/sortnet.sock or tcp:host:7000")
+	set := flags.String("set", "partitioned-ordered", "output set implementation: "+names(outputSets))
+	workers := flags.Int("workers", runtime.NumCPU(), "number of goroutines used for pruning")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	config := distributed.WorkerConfig{Workers: *workers}
+
+	var err error
+	if config.NewSet, err = lookup("output set", outputSets, *set); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return distributed.Work(ctx, *connect, config)
+}
@@ -82,11 +82,12 @@ func GenerateNetworks(comparators []sortnet.Comparator, networks []sortnet.Netwo
 	return derivatives
 }
 
+// GenerateOutputSets evaluates every network for all inputs, 64 inputs at a time.
 func GenerateOutputSets(networks []sortnet.Network) []sortnet.OutputSet {
+	empty := NewSet(Channels).NewEmpty()
 	sets := make([]sortnet.OutputSet, len(networks))
 	for i, network := range networks {
-		complete := NewSet(Channels)
-		sets[i] = complete.Derive(network)
+		sets[i] = sortnet.BitSlicedOutputSet(empty.NewEmpty(), network.(*sortnet.ComparatorNetwork), Channels)
 	}
 
 	return sets
@@ -55,6 +55,10 @@ func run() {
 		networks = NetworksWithNonNilOutputset(outputSets, networks)
 		fmt.Printf("\tpruned %d networks - %d remaining\n", before-len(networks), len(networks))
 
+		if network, ok := example.Discovered(rounds+1, networks); ok {
+			networks = []sortnet.Network{network}
+			break
+		}
 		if len(networks) == 1 && rounds > 1 {
 			break
 		}
@@ -108,12 +112,13 @@ func GenerateNetworks(comparators []sortnet.Comparator, networks []sortnet.Netwo
 	return derivatives
 }
 
+// GenerateOutputSets evaluates every network for all inputs, 64 inputs at a time.
 func GenerateOutputSets(networks []sortnet.Network) []sortnet.OutputSet {
+	empty := NewSet(Channels).NewEmpty()
 	derivatives := make([]sortnet.OutputSet, len(networks))
 
 	for i, network := range networks {
-		complete := NewSet(Channels)
-		derivatives[i] = complete.Derive(network)
+		derivatives[i] = sortnet.BitSlicedOutputSet(empty.NewEmpty(), network.(*sortnet.ComparatorNetwork), Channels)
 	}
 
 	return derivatives
@@ -129,6 +134,17 @@ func Subsumes(a, b sortnet.OutputSet) bool {
 	})
 }
 
+// SubsumedBy tests the set against each of the subsumers, see example.Subsumers.
+func SubsumedBy(subsumers []sortnet.OutputSet, target sortnet.OutputSet) bool {
+	for _, a := range subsumers {
+		if SubsumptionTest(a.Metadata(), target.Metadata()) && Subsumes(a, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
 type PruneMethod = func(currentID int, sets []sortnet.OutputSet) []int
 
 func Prune(sets []sortnet.OutputSet) {
@@ -145,17 +161,15 @@ func Prune(sets []sortnet.OutputSet) {
 }
 
 func PruneSerial(currentID int, sets []sortnet.OutputSet) []int {
+	subsumers := example.Subsumers(sets[currentID])
+
 	var ids []int
 	for id, target := range sets {
 		if currentID == id || target == nil {
 			continue
 		}
 
-		if !SubsumptionTest(sets[currentID].Metadata(), target.Metadata()) {
-			continue
-		}
-
-		if Subsumes(sets[currentID], target) {
+		if SubsumedBy(subsumers, target) {
 			ids = append(ids, id)
 		}
 	}
@@ -171,6 +185,7 @@ type Work struct {
 func PruneParallel(currentID int, sets []sortnet.OutputSet) []int {
 	g, _ := errgroup.WithContext(context.Background())
 	workChan := make(chan *Work, Workers)
+	subsumers := example.Subsumers(sets[currentID])
 
 	g.Go(func() error {
 		defer close(workChan)
@@ -180,10 +195,6 @@ func PruneParallel(currentID int, sets []sortnet.OutputSet) []int {
 				continue
 			}
 
-			if !SubsumptionTest(sets[currentID].Metadata(), target.Metadata()) {
-				continue
-			}
-
 			workChan <- &Work{
 				set: target,
 				id:  id,
@@ -197,7 +208,7 @@ func PruneParallel(currentID int, sets []sortnet.OutputSet) []int {
 	for i := 0; i < Workers; i++ {
 		g.Go(func() error {
 			for work := range workChan {
-				if Subsumes(sets[currentID], work.set) {
+				if SubsumedBy(subsumers, work.set) {
 					subsumedChan <- work.id
 				}
 			}
@@ -41,22 +41,28 @@ func run() {
 	networks := []sortnet.Network{
 		&sortnet.ComparatorNetwork{},
 	}
+	sets := []sortnet.OutputSet{
+		NewSet(Channels),
+	}
 
 	k := 0
 	for {
 		fmt.Printf("Round %d\n", k)
 		k++
 
-		var sets []sortnet.OutputSet
-		networks, sets = Generate(allComparators, networks)
+		networks, sets = Generate(allComparators, networks, sets)
 		fmt.Printf("\tgenerated %d networks & output sets\n", len(networks))
 
 		sets = Prune(sets)
 
 		before := len(networks)
-		networks = NetworksWithNonNilOutputset(sets, networks)
+		networks, sets = Survivors(networks, sets)
 		fmt.Printf("\tpruned %d networks - %d remaining\n", before-len(networks), len(networks))
 
+		if network, ok := example.Discovered(k, networks); ok {
+			networks = []sortnet.Network{network}
+			break
+		}
 		if len(networks) == 1 && k > 1 || k > 50 {
 			break
 		}
@@ -73,35 +79,31 @@ func run() {
 	fmt.Println(sortingNetwork)
 }
 
-func NetworksWithNonNilOutputset(sets []sortnet.OutputSet, networks []sortnet.Network) []sortnet.Network {
+// Survivors returns the networks whose output set was not pruned, together with their output sets.
+func Survivors(networks []sortnet.Network, sets []sortnet.OutputSet) ([]sortnet.Network, []sortnet.OutputSet) {
+	remainingNetworks := make([]sortnet.Network, 0, len(networks))
+	remainingSets := make([]sortnet.OutputSet, 0, len(sets))
 	for i := range sets {
-		if sets[i] == nil {
-			networks[i] = nil
-		}
-	}
-
-	for i := 0; i < len(networks); i++ {
-		if networks[i] == nil {
-			networks[i] = networks[len(networks)-1]
-			networks = networks[:len(networks)-1]
-			i--
+		if sets[i] != nil {
+			remainingNetworks = append(remainingNetworks, networks[i])
+			remainingSets = append(remainingSets, sets[i])
 		}
 	}
 
-	return networks
+	return remainingNetworks, remainingSets
 }
 
-func Generate(comparators []sortnet.Comparator, networks []sortnet.Network) ([]sortnet.Network, []sortnet.OutputSet) {
+// Generate derives the children of every network, where parentSets holds the output set of every network.
+func Generate(comparators []sortnet.Comparator, networks []sortnet.Network, parentSets []sortnet.OutputSet) ([]sortnet.Network, []sortnet.OutputSet) {
 	derivatives := make([]sortnet.Network, 0, len(networks))
 	sets := make([]sortnet.OutputSet, 0, len(networks))
 
-	for _, network := range networks {
-		set := NewSet(Channels)
-		set = set.Derive(network)
-
+	for i, network := range networks {
+		set := parentSets[i]
 		for _, child := range network.Derive(comparators) {
-			childSet := NewSet(Channels)
-			childSet = childSet.Derive(child)
+			// only the comparator added by the child has to be applied to the parent set
+			added := child.(*sortnet.ComparatorNetwork)
+			childSet := sortnet.DeriveFrom(set, added.Tail(added.Len()-1)...)
 
 			if subsumes(set, childSet) {
 				continue
@@ -212,18 +214,21 @@ func Prune(sets []sortnet.OutputSet) []sortnet.OutputSet {
 type PruneMethod = func(currentID int, sets []sortnet.OutputSet, tree *KDTree) []int
 
 func PruneSerial(currentID int, sets []sortnet.OutputSet, tree *KDTree) []int {
-	base := CreateMetadataPoint(sets[currentID])
-	indexes := tree.FindCandidates(base)
-
 	var ids []int
-	for _, i := range indexes {
-		target := sets[i]
-		if currentID == i || target == nil {
-			continue
-		}
+	subsumed := map[int]bool{}
+	for _, subsumer := range example.Subsumers(sets[currentID]) {
+		indexes := tree.FindCandidates(CreateMetadataPoint(subsumer))
 
-		if subsumesByPermutation(sets[currentID], target) {
-			ids = append(ids, i)
+		for _, i := range indexes {
+			target := sets[i]
+			if currentID == i || target == nil || subsumed[i] {
+				continue
+			}
+
+			if subsumesByPermutation(subsumer, target) {
+				subsumed[i] = true
+				ids = append(ids, i)
+			}
 		}
 	}
 
@@ -231,8 +236,9 @@ func PruneSerial(currentID int, sets []sortnet.OutputSet, tree *KDTree) []int {
 }
 
 type Work struct {
-	set sortnet.OutputSet
-	id  int
+	subsumer sortnet.OutputSet
+	set      sortnet.OutputSet
+	id       int
 }
 
 func PruneParallel(currentID int, sets []sortnet.OutputSet, tree *KDTree) []int {
@@ -241,18 +247,20 @@ func PruneParallel(currentID int, sets []sortnet.OutputSet, tree *KDTree) []int
 
 	g.Go(func() error {
 		defer close(workChan)
-		base := CreateMetadataPoint(sets[currentID])
-		indexes := tree.FindCandidates(base)
+		for _, subsumer := range example.Subsumers(sets[currentID]) {
+			indexes := tree.FindCandidates(CreateMetadataPoint(subsumer))
 
-		for _, i := range indexes {
-			target := sets[i]
-			if currentID == i || target == nil {
-				continue
-			}
+			for _, i := range indexes {
+				target := sets[i]
+				if currentID == i || target == nil {
+					continue
+				}
 
-			workChan <- &Work{
-				set: target,
-				id:  i,
+				workChan <- &Work{
+					subsumer: subsumer,
+					set:      target,
+					id:       i,
+				}
 			}
 		}
 		return nil
@@ -263,7 +271,7 @@ func PruneParallel(currentID int, sets []sortnet.OutputSet, tree *KDTree) []int
 	for i := 0; i < Workers; i++ {
 		g.Go(func() error {
 			for work := range workChan {
-				if subsumesByPermutation(sets[currentID], work.set) {
+				if subsumesByPermutation(work.subsumer, work.set) {
 					subsumedChan <- work.id
 				}
 			}
@@ -113,8 +113,9 @@ func (c *Container) SearchParallel(set sortnet.OutputSet, result chan<- *Work) {
 					}
 
 					result <- &Work{
-						set: target,
-						id:  target.Metadata().NetworkID,
+						subsumer: set,
+						set:      target,
+						id:       target.Metadata().NetworkID,
 					}
 				}
 			}
@@ -45,6 +45,9 @@ func run() {
 	networks := []sortnet.Network{
 		&sortnet.ComparatorNetwork{},
 	}
+	sets := []sortnet.OutputSet{
+		NewSet(Channels),
+	}
 
 	k := 0
 	for {
@@ -52,15 +55,18 @@ func run() {
 		fmt.Printf("Round %d\n", k+1)
 		k++
 
-		var sets []sortnet.OutputSet
-		networks, sets = Generate(allComparators, networks)
+		networks, sets = Generate(allComparators, networks, sets)
 
 		sets = Prune(sets)
 
 		before := len(networks)
-		networks = NetworksWithNonNilOutputset(sets, networks)
+		networks, sets = Survivors(networks, sets)
 		fmt.Printf("\tpruned %d networks - %d remaining\n", before-len(networks), len(networks))
 
+		if network, ok := example.Discovered(k, networks); ok {
+			networks = []sortnet.Network{network}
+			break
+		}
 		if len(networks) == 1 && k > 1 || k == FiltersLimit {
 			break
 		}
@@ -72,16 +78,19 @@ func run() {
 	}
 }
 
-func NetworksWithNonNilOutputset(sets []sortnet.OutputSet, networks []sortnet.Network) []sortnet.Network {
-	selected := make([]sortnet.Network, 0, len(networks))
+// Survivors returns the networks whose output set was not pruned, together with their output sets.
+func Survivors(networks []sortnet.Network, sets []sortnet.OutputSet) ([]sortnet.Network, []sortnet.OutputSet) {
+	selectedNetworks := make([]sortnet.Network, 0, len(networks))
+	selectedSets := make([]sortnet.OutputSet, 0, len(sets))
 	for i := range sets {
 		if sets[i] != nil {
 			id := sets[i].Metadata().NetworkID
-			selected = append(selected, networks[int(id)])
+			selectedNetworks = append(selectedNetworks, networks[int(id)])
+			selectedSets = append(selectedSets, sets[i])
 		}
 	}
 
-	return selected
+	return selectedNetworks, selectedSets
 }
 
 type GenerateWork struct {
@@ -89,7 +98,8 @@ type GenerateWork struct {
 	set     sortnet.OutputSet
 }
 
-func Generate(comparators []sortnet.Comparator, networks []sortnet.Network) ([]sortnet.Network, []sortnet.OutputSet) {
+// Generate derives the children of every network, where sets holds the output set of every network.
+func Generate(comparators []sortnet.Comparator, networks []sortnet.Network, sets []sortnet.OutputSet) ([]sortnet.Network, []sortnet.OutputSet) {
 	bar := pb.StartNew(len(networks))
 	defer bar.Finish()
 	defer bar.SetCurrent(bar.Total())
@@ -101,8 +111,8 @@ func Generate(comparators []sortnet.Comparator, networks []sortnet.Network) ([]s
 
 	go func() {
 		defer close(workChan)
-		for _, network := range networks {
-			workChan <- &GenerateWork{network: network}
+		for i, network := range networks {
+			workChan <- &GenerateWork{network: network, set: sets[i]}
 		}
 	}()
 
@@ -115,13 +125,12 @@ func Generate(comparators []sortnet.Comparator, networks []sortnet.Network) ([]s
 			var localID sortnet.NetworkID
 
 			for work := range workChan {
-				set := NewSet(Channels)
-				set = set.Derive(work.network)
-
+				set := work.set
 				left := localID
 				for _, child := range work.network.Derive(comparators) {
-					childSet := NewSet(Channels)
-					childSet = childSet.Derive(child)
+					// only the comparator added by the child has to be applied to the parent set
+					added := child.(*sortnet.ComparatorNetwork)
+					childSet := sortnet.DeriveFrom(set, added.Tail(added.Len()-1)...)
 					childSet.Metadata().NetworkID = localID
 
 					if set.Size() == childSet.Size() && set.IsSubset(childSet, nil) {
@@ -164,17 +173,17 @@ func Generate(comparators []sortnet.Comparator, networks []sortnet.Network) ([]s
 	}
 
 	var derivatives []sortnet.Network
-	var sets []sortnet.OutputSet
+	var derivedSets []sortnet.OutputSet
 	var id sortnet.NetworkID
 	for work := range mergeChan {
 		work.set.Metadata().NetworkID = id
 		id++
 
 		derivatives = append(derivatives, work.network)
-		sets = append(sets, work.set)
+		derivedSets = append(derivedSets, work.set)
 	}
 
-	return derivatives, sets
+	return derivatives, derivedSets
 }
 
 func CreateMetadataPoint(setAbstraction sortnet.OutputSet) *Metadata {
@@ -239,23 +248,29 @@ func Prune(sets []sortnet.OutputSet) []sortnet.OutputSet {
 type PruneMethod = func(currentID int, sets []sortnet.OutputSet, tree *Container) []sortnet.NetworkID
 
 func PruneSerial(currentID int, sets []sortnet.OutputSet, tree *Container) []sortnet.NetworkID {
-	panic("wip")
-	//set := sets[currentID]
-	//targets := tree.Search(set)
-	//
-	//var ids []sortnet.NetworkID
-	//for _, target := range targets {
-	//	if subsumesByPermutation(set, target) {
-	//		ids = append(ids, target.Metadata().NetworkID)
-	//	}
-	//}
-	//
-	//return ids
+	var ids []sortnet.NetworkID
+	subsumed := map[sortnet.NetworkID]bool{}
+	for _, subsumer := range example.Subsumers(sets[currentID]) {
+		for _, id := range tree.Search(subsumer, DirectionEqualAndSuperset) {
+			target := tree.sets[int(id)]
+			// the reflection is not the set itself, so the set may be among the candidates
+			if subsumed[id] || target == sets[currentID] {
+				continue
+			}
+			if subsumesByPermutation(subsumer, target) {
+				subsumed[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return ids
 }
 
 type Work struct {
-	set sortnet.OutputSet
-	id  sortnet.NetworkID
+	subsumer sortnet.OutputSet
+	set      sortnet.OutputSet
+	id       sortnet.NetworkID
 }
 
 func PruneParallel(currentID int, sets []sortnet.OutputSet, tree *Container) []sortnet.NetworkID {
@@ -263,7 +278,9 @@ func PruneParallel(currentID int, sets []sortnet.OutputSet, tree *Container) []s
 
 	go func() {
 		defer close(workChan)
-		tree.SearchParallel(sets[currentID], workChan)
+		for _, subsumer := range example.Subsumers(sets[currentID]) {
+			tree.SearchParallel(subsumer, workChan)
+		}
 	}()
 
 	subsumedChan := make(chan sortnet.NetworkID)
@@ -271,7 +288,11 @@ func PruneParallel(currentID int, sets []sortnet.OutputSet, tree *Container) []s
 	for i := 0; i < Workers; i++ {
 		go func() {
 			for work := range workChan {
-				if subsumesByPermutation(sets[currentID], work.set) {
+				// the reflection is not the set itself, so the set may be among the candidates
+				if work.set == sets[currentID] {
+					continue
+				}
+				if subsumesByPermutation(work.subsumer, work.set) {
 					subsumedChan <- work.id
 				}
 			}
@@ -283,8 +304,12 @@ func PruneParallel(currentID int, sets []sortnet.OutputSet, tree *Container) []s
 	}
 
 	var ids []sortnet.NetworkID
+	subsumed := map[sortnet.NetworkID]bool{}
 	for id := range subsumedChan {
-		ids = append(ids, id)
+		if !subsumed[id] {
+			subsumed[id] = true
+			ids = append(ids, id)
+		}
 	}
 	return ids
 }
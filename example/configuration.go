@@ -3,6 +3,7 @@ package example
 import (
 	"fmt"
 	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/known"
 	"github.com/andersfylling/go-sortnet/sortnet/outputset"
 )
 
@@ -19,6 +20,10 @@ var (
 	GeneratePermutations sortnet.GeneratePermutationsFunc = sortnet.GeneratePermutationsByBitmap
 	NewSet               outputset.NewSet                 = outputset.NewPartitionedOrdered
 	PruningStrategy      PruningStrategyType              = ParallelPruning
+
+	// Reflection also prunes by the reflection of every output set, see sortnet.ReflectOutputSet. Off by default; set
+	// it to true to have the pruners of every example test the reflections through Subsumers as well.
+	Reflection = false
 )
 
 type PruningStrategyType int
@@ -28,6 +33,36 @@ const (
 	SerialPruning
 )
 
+// Discovered returns a sorting network among the networks once they reach the proven optimal number of comparators,
+// such that the search can stop without waiting for a single network to remain.
+func Discovered(comparators int, networks []sortnet.Network) (sortnet.Network, bool) {
+	best, ok := known.BestBySize(Channels)
+	if !ok || !best.Proven || comparators < best.Size {
+		return nil, false
+	}
+
+	for _, network := range networks {
+		if network == nil {
+			continue
+		}
+		if sorting, _ := sortnet.IsSortingNetwork(network, Channels); sorting {
+			return network, true
+		}
+	}
+
+	return nil, false
+}
+
+// Subsumers returns the output sets to test against the other sets of a round on behalf of the set: the set itself,
+// and its reflection when Reflection is set.
+func Subsumers(set sortnet.OutputSet) []sortnet.OutputSet {
+	if !Reflection {
+		return []sortnet.OutputSet{set}
+	}
+
+	return []sortnet.OutputSet{set, sortnet.ReflectOutputSet(set, Channels)}
+}
+
 func init() {
 	fmt.Println()
 	fmt.Println("###############################################")
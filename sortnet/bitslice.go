@@ -0,0 +1,122 @@
+package sortnet
+
+import "math/bits"
+
+// BitSlice evaluates a network for many inputs at once. Every channel is a bitmap over the inputs, where bit i holds
+// the value of the channel for input i, so a comparator is applied to 64 inputs by a single AND and OR.
+type BitSlice struct {
+	inputs   int
+	channels [][]uint64
+}
+
+// NewBitSlice creates the bitmaps of the given inputs.
+func NewBitSlice(channels int, inputs []BinarySequence) *BitSlice {
+	b := newBitSlice(channels, len(inputs))
+	for i, input := range inputs {
+		for it := NewSequenceIterator(input); !it.Empty(); {
+			channel := it.Next()
+			b.channels[channel][i/64] |= 1 << (i % 64)
+		}
+	}
+	return b
+}
+
+// NewBitSliceOfAll creates the bitmaps of all 2^channels inputs, where input i is the binary sequence i.
+func NewBitSliceOfAll(channels int) *BitSlice {
+	b := newBitSlice(channels, 1<<channels)
+	for channel := range b.channels {
+		// the first 6 channels repeat a pattern within every word, eg. 0b...1010 for channel 0, and the others alternate
+		// between words of only zeros and only ones
+		pattern := ^uint64(0)
+		if channel < 6 {
+			pattern = ^uint64(0) / (1<<(1<<channel) + 1) << (1 << channel)
+		}
+
+		for word := range b.channels[channel] {
+			if channel < 6 || (word>>(channel-6))&1 == 1 {
+				b.channels[channel][word] = pattern
+			}
+		}
+	}
+	return b
+}
+
+func newBitSlice(channels, inputs int) *BitSlice {
+	b := &BitSlice{
+		inputs:   inputs,
+		channels: make([][]uint64, channels),
+	}
+	words := (inputs + 63) / 64
+	for channel := range b.channels {
+		b.channels[channel] = make([]uint64, words)
+	}
+	return b
+}
+
+// Apply applies the comparators to every input. A comparator moves a set bit from the From channel to the To channel,
+// so To becomes the disjunction and From the conjunction of both channels.
+func (b *BitSlice) Apply(comparators []Comparator) {
+	for _, comparator := range comparators {
+		from, to := b.channels[comparator.From], b.channels[comparator.To]
+		for word := range from {
+			from[word], to[word] = from[word]&to[word], from[word]|to[word]
+		}
+	}
+}
+
+// Each calls fn with the current value of every input, in the order of the inputs.
+func (b *BitSlice) Each(fn func(BinarySequence)) {
+	var block [64]uint64
+	for word := 0; word*64 < b.inputs; word++ {
+		block = [64]uint64{}
+		for channel := range b.channels {
+			block[channel] = b.channels[channel][word]
+		}
+		transpose(&block)
+
+		for i := 0; i < 64 && word*64+i < b.inputs; i++ {
+			fn(BinarySequence(block[i]))
+		}
+	}
+}
+
+// transpose mirrors the 64x64 bit matrix along its diagonal, such that bit j of row i becomes bit i of row j. Each
+// step swaps the off-diagonal blocks of half the size, see Hacker's Delight, section 7-3.
+func transpose(m *[64]uint64) {
+	mask := uint64(0x00000000ffffffff)
+	for j := 32; j != 0; j >>= 1 {
+		for k := 0; k < 64; k += 2 * j {
+			for i := k; i < k+j; i++ {
+				t := (m[i]>>j ^ m[i+j]) & mask
+				m[i] ^= t << j
+				m[i+j] ^= t
+			}
+		}
+		mask ^= mask << (j >> 1)
+	}
+}
+
+// BitSlicedOutputSet adds the output of the network for every input of the channels to the set, and returns it. The
+// sorted inputs of only zeros and only ones are left out, like PopulateOutputSet does. The result equals
+// set.Derive(network) on a populated set, but is computed for 64 inputs at a time, and every distinct output is added
+// once in increasing order.
+func BitSlicedOutputSet(set OutputSet, network *ComparatorNetwork, channels int) OutputSet {
+	b := NewBitSliceOfAll(channels)
+	b.Apply(network.comparators)
+
+	seen := make([]uint64, (1<<channels+63)/64)
+	b.Each(func(output BinarySequence) {
+		seen[output/64] |= 1 << (output % 64)
+	})
+
+	mask := SequenceMask(channels)
+	for word := range seen {
+		for ; seen[word] != 0; seen[word] &= seen[word] - 1 {
+			output := BinarySequence(word*64 + bits.TrailingZeros64(seen[word]))
+			if output != 0 && output != mask {
+				set.Add(output)
+			}
+		}
+	}
+	return set
+}
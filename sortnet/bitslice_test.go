@@ -0,0 +1,110 @@
+package sortnet
+
+import "testing"
+
+func TestBitSlice(t *testing.T) {
+	for channels := 1; channels <= 10 && channels <= MaxChannels; channels++ {
+		network := NewBoseNelsonSort(channels)
+		prefix := network.comparators[:len(network.comparators)/2]
+
+		var inputs []BinarySequence
+		b := NewBitSliceOfAll(channels)
+		b.Each(func(input BinarySequence) {
+			inputs = append(inputs, input)
+		})
+		if len(inputs) != 1<<channels {
+			t.Fatalf("expected %d inputs, got %d", 1<<channels, len(inputs))
+		}
+		for i, input := range inputs {
+			if input != BinarySequence(i) {
+				t.Fatalf("%d channels: expected input %b, got %b", channels, i, input)
+			}
+		}
+
+		b.Apply(prefix)
+		partial := &ComparatorNetwork{comparators: prefix}
+		var i int
+		b.Each(func(output BinarySequence) {
+			if expected := partial.Transform(BinarySequence(i)); output != expected {
+				t.Errorf("%d channels: expected %b for input %b, got %b", channels, expected, i, output)
+			}
+			i++
+		})
+
+		// continue from the outputs of the prefix with the rest of the network
+		var outputs []BinarySequence
+		NewBitSliceOfAll(channels).Each(func(input BinarySequence) {
+			outputs = append(outputs, partial.Transform(input))
+		})
+		rest := NewBitSlice(channels, outputs)
+		rest.Apply(network.comparators[len(prefix):])
+		rest.Each(func(output BinarySequence) {
+			if !output.IsSorted() {
+				t.Errorf("%d channels: expected sorted outputs, got %b", channels, output)
+			}
+		})
+	}
+}
+
+func BenchmarkBitSlice(b *testing.B) {
+	network := NewBatcherOddEvenMergeSort(MaxChannels)
+	if MaxChannels > 16 {
+		network = NewBatcherOddEvenMergeSort(16)
+	}
+	channels := network.Channels()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := NewBitSliceOfAll(channels)
+		s.Apply(network.comparators)
+		s.Each(func(BinarySequence) {})
+	}
+}
+
+func BenchmarkTransformAll(b *testing.B) {
+	network := NewBatcherOddEvenMergeSort(16)
+	if MaxChannels < 16 {
+		b.Skip()
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for input := 0; input < 1<<16; input++ {
+			network.Transform(BinarySequence(input))
+		}
+	}
+}
+
+func TestBitSlicedOutputSet(t *testing.T) {
+	channels := 10
+	if MaxChannels < channels {
+		channels = MaxChannels
+	}
+	network := NewPairwiseSort(channels)
+	prefix := &ComparatorNetwork{comparators: network.comparators[:len(network.comparators)/2]}
+
+	set := BitSlicedOutputSet(&sequenceSet{}, prefix, channels).(*sequenceSet)
+	expected := map[BinarySequence]bool{}
+	for input := BinarySequence(1); input < SequenceMask(channels); input++ {
+		expected[prefix.Transform(input)] = true
+	}
+
+	if len(set.sequences) != len(expected) {
+		t.Errorf("expected %d sequences, got %d", len(expected), len(set.sequences))
+	}
+	for i, seq := range set.sequences {
+		if !expected[seq] || (i > 0 && seq <= set.sequences[i-1]) {
+			t.Errorf("unexpected sequence %b", seq)
+		}
+	}
+}
+
+// sequenceSet records the added sequences, to check what BitSlicedOutputSet adds.
+type sequenceSet struct {
+	OutputSet
+	sequences []BinarySequence
+}
+
+func (s *sequenceSet) Add(seq BinarySequence) {
+	s.sequences = append(s.sequences, seq)
+}
@@ -0,0 +1,174 @@
+// Package cert writes and reads proof certificates of a generate-and-prune search. A certificate lists, per round,
+// every pruned network with the network that subsumes it and the permutation that proves it, followed by the
+// networks that survived the round. This is enough to check the lower bound found by a search without trusting the
+// search itself: the checker derives the same children again and only has to test the recorded witnesses.
+//
+// A certificate is a JSON object per line. It starts with a Header, followed by the Pruned records and a Round record
+// per round. The networks of a round are identified by their position in the enumeration of the children: child id
+// p*e+i extends survivor p of the previous round by extension i of Extensions, where e is the number of extensions.
+// Round 0 has a single survivor, the empty network. Children that are neither pruned nor survivors must be
+// redundant, meaning their output set equals the output set of their parent.
+package cert
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+// Version is increased whenever the format of a certificate changes.
+const Version = 1
+
+// Mode is what a round appends to the networks, as in search.Mode.
+type Mode string
+
+const (
+	SizeMode  Mode = "size"
+	DepthMode Mode = "depth"
+)
+
+const (
+	headerType = "header"
+	prunedType = "pruned"
+	roundType  = "round"
+)
+
+// Header describes the search, which decides the children of every round.
+type Header struct {
+	Type      string `json:"type"`
+	Version   int    `json:"version"`
+	Channels  int    `json:"channels"`
+	Mode      Mode   `json:"mode"`
+	AllLayers bool   `json:"all_layers,omitempty"`
+}
+
+// Pruned records that the output set of child ID is subsumed by the output set of child By of the same round: after
+// applying Permutation to the set of By, or to its reflection when Reflected is set, it is a subset of the set of ID.
+// By may itself be pruned later in the round, as long as the chain ends in a survivor.
+type Pruned struct {
+	Type        string                 `json:"type"`
+	Round       int                    `json:"round"`
+	ID          int                    `json:"id"`
+	By          int                    `json:"by"`
+	Permutation sortnet.PermutationMap `json:"permutation"`
+	Reflected   bool                   `json:"reflected,omitempty"`
+}
+
+// Round ends a round. Survivors holds the children passed on to the next round in increasing order. The last round
+// instead lists the children that are sorting networks in Sorting.
+type Round struct {
+	Type      string `json:"type"`
+	Round     int    `json:"round"`
+	Survivors []int  `json:"survivors,omitempty"`
+	Sorting   []int  `json:"sorting,omitempty"`
+}
+
+// Extensions returns what a round appends to every survivor of the previous one, one slice per child: every
+// comparator in SizeMode, and every layer in DepthMode. The first layer is fixed to (0,1),(2,3),..., and later
+// layers are maximal unless allLayers is set.
+func Extensions(channels int, mode Mode, allLayers bool, round int) [][]sortnet.Comparator {
+	switch {
+	case mode == SizeMode:
+		var extensions [][]sortnet.Comparator
+		for _, comparator := range sortnet.AllComparatorCombinations(channels) {
+			extensions = append(extensions, []sortnet.Comparator{comparator})
+		}
+		return extensions
+	case round == 1:
+		var first []sortnet.Comparator
+		for channel := 0; channel+1 < channels; channel += 2 {
+			first = append(first, sortnet.Comparator{From: channel + 1, To: channel})
+		}
+		return [][]sortnet.Comparator{first}
+	default:
+		return sortnet.Matchings(channels, !allLayers)
+	}
+}
+
+// Writer writes the records of a certificate. Records are buffered until the end of a round.
+type Writer struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func NewWriter(w io.Writer) *Writer {
+	buffered := bufio.NewWriter(w)
+	return &Writer{w: buffered, enc: json.NewEncoder(buffered)}
+}
+
+func (w *Writer) Header(header Header) error {
+	header.Type, header.Version = headerType, Version
+	if err := w.enc.Encode(header); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+func (w *Writer) Pruned(pruned Pruned) error {
+	pruned.Type = prunedType
+	return w.enc.Encode(pruned)
+}
+
+// Flush writes the buffered records of the current round.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Round writes the end of the round and flushes the records of the round.
+func (w *Writer) Round(round Round) error {
+	round.Type = roundType
+	if err := w.enc.Encode(round); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+var ErrFormat = errors.New("malformed certificate")
+
+// Reader reads the records of a certificate.
+type Reader struct {
+	dec *json.Decoder
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{dec: json.NewDecoder(bufio.NewReader(r))}
+}
+
+// Next returns the next record, which is a *Header, *Pruned or *Round. Returns io.EOF after the last record.
+func (r *Reader) Next() (interface{}, error) {
+	var raw json.RawMessage
+	if err := r.dec.Decode(&raw); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+
+	var kind struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &kind); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+
+	var record interface{}
+	switch kind.Type {
+	case headerType:
+		record = &Header{}
+	case prunedType:
+		record = &Pruned{}
+	case roundType:
+		record = &Round{}
+	default:
+		return nil, fmt.Errorf("%w: unknown record type %q", ErrFormat, kind.Type)
+	}
+	if err := json.Unmarshal(raw, record); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+
+	return record, nil
+}
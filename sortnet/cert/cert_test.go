@@ -0,0 +1,117 @@
+package cert
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+func TestWriterReader(t *testing.T) {
+	records := []interface{}{
+		&Header{Type: headerType, Version: Version, Channels: 4, Mode: DepthMode},
+		&Pruned{Type: prunedType, Round: 2, ID: 3, By: 1, Permutation: sortnet.PermutationMap{1, 0, 3, 2}, Reflected: true},
+		&Round{Type: roundType, Round: 2, Survivors: []int{0, 1}},
+		&Round{Type: roundType, Round: 3, Sorting: []int{4}},
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, record := range records {
+		var err error
+		switch record := record.(type) {
+		case *Header:
+			err = w.Header(*record)
+		case *Pruned:
+			err = w.Pruned(*record)
+		case *Round:
+			err = w.Round(*record)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewReader(&buf)
+	for i, expected := range records {
+		record, err := r.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(record, expected) {
+			t.Errorf("record %d: expected %+v, got %+v", i, expected, record)
+		}
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("expected the end of the certificate, got %v", err)
+	}
+
+	if _, err := NewReader(strings.NewReader(`{"type":"lemma"}`)).Next(); !errors.Is(err, ErrFormat) {
+		t.Errorf("expected a format error for an unknown record, got %v", err)
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	if n := len(Extensions(5, SizeMode, false, 3)); n != 10 {
+		t.Errorf("expected a comparator per pair of 5 channels, got %d", n)
+	}
+	if first := Extensions(5, DepthMode, false, 1); len(first) != 1 || len(first[0]) != 2 {
+		t.Errorf("expected the fixed first layer of 2 comparators, got %v", first)
+	}
+	if n := len(Extensions(4, DepthMode, false, 2)); n != 3 {
+		t.Errorf("expected 3 maximal layers of 4 channels, got %d", n)
+	}
+}
+
+// TestExtensionsMatchings checks the layers of a depth round against every subset of the comparators of the channels,
+// as the checker relies on them to derive the children.
+func TestExtensionsMatchings(t *testing.T) {
+	for channels := 2; channels <= 6; channels++ {
+		comparators := sortnet.AllComparatorCombinations(channels)
+		for _, allLayers := range []bool{false, true} {
+			expected := map[string]bool{}
+			for subset := 1; subset < 1<<len(comparators); subset++ {
+				var layer []sortnet.Comparator
+				var used sortnet.BinarySequence
+				disjoint := true
+				for i, comparator := range comparators {
+					if subset&(1<<i) == 0 {
+						continue
+					}
+					pair := sortnet.BinarySequence(1)<<comparator.From | 1<<comparator.To
+					disjoint = disjoint && used&pair == 0
+					used |= pair
+					layer = append(layer, comparator)
+				}
+				if disjoint && (allLayers || channels-used.OnesCount() <= 1) {
+					expected[layerKey(layer)] = true
+				}
+			}
+
+			extensions := Extensions(channels, DepthMode, allLayers, 2)
+			found := map[string]bool{}
+			for _, layer := range extensions {
+				key := layerKey(layer)
+				if found[key] || !expected[key] {
+					t.Errorf("%d channels: unexpected or repeated layer %s", channels, key)
+				}
+				found[key] = true
+			}
+			if len(found) != len(expected) {
+				t.Errorf("%d channels, all layers %t: expected %d layers, got %d", channels, allLayers, len(expected), len(found))
+			}
+		}
+	}
+}
+
+// layerKey identifies a layer regardless of the order of its comparators.
+func layerKey(layer []sortnet.Comparator) string {
+	sorted := append([]sortnet.Comparator{}, layer...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].To < sorted[j].To })
+	return sortnet.FormatComparators(sorted)
+}
@@ -0,0 +1,268 @@
+package cert
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+var ErrInvalid = errors.New("invalid certificate")
+
+// Result is what a checked certificate proves: given the children the header derives per round, no sorting network
+// exists with fewer than Bound comparators, or layers in DepthMode, and Sorting networks of that bound were found.
+type Result struct {
+	Channels int
+	Mode     Mode
+	Bound    int
+	Sorting  []*sortnet.ComparatorNetwork
+}
+
+// set is an output set as a plain map, rather than one of the optimised sets of the search.
+type set map[sortnet.BinarySequence]bool
+
+func (s set) isSubset(other set) bool {
+	for seq := range s {
+		if !other[seq] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s set) sorted() bool {
+	for seq := range s {
+		if seq&(seq+1) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// apply returns the set after the comparators.
+func (s set) apply(channels int, comparators []sortnet.Comparator) set {
+	network, _ := sortnet.NewComparatorNetwork(channels, comparators...)
+	applied := set{}
+	for seq := range s {
+		applied[network.Transform(seq)] = true
+	}
+	return applied
+}
+
+// permute returns the set with the permutation applied to every sequence, after reflecting them when reflected is set.
+func (s set) permute(channels int, permutation sortnet.PermutationMap, reflected bool) set {
+	permuted := set{}
+	for seq := range s {
+		if reflected {
+			seq = sortnet.Reflect(seq, channels)
+		}
+		permuted[sortnet.ApplyPermutation(seq, permutation)] = true
+	}
+	return permuted
+}
+
+type child struct {
+	network *sortnet.ComparatorNetwork
+	set     set
+	parent  set
+}
+
+// Check replays the certificate round by round. Every child of a round is derived again from the survivors of the
+// previous round, and must be accounted for by the certificate: a survivor, redundant, or pruned by a subsumption
+// that is tested again with the recorded permutation. No child may sort before the last round, and the last round
+// must list its sorting networks.
+//
+// The checker only shares the network and sequence primitives of package sortnet with the search, and keeps output
+// sets as plain maps over every input, including the all zeros and all ones inputs.
+func Check(r io.Reader) (*Result, error) {
+	reader := NewReader(r)
+	record, err := reader.Next()
+	if err != nil {
+		return nil, err
+	}
+	header, ok := record.(*Header)
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("%w: expected a header first", ErrInvalid)
+	case header.Version != Version:
+		return nil, fmt.Errorf("%w: version %d is not supported, expected %d", ErrInvalid, header.Version, Version)
+	case header.Channels < 1 || header.Channels > sortnet.MaxChannels:
+		return nil, fmt.Errorf("%w: %d channels", ErrInvalid, header.Channels)
+	case header.Mode != SizeMode && header.Mode != DepthMode:
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalid, header.Mode)
+	}
+	channels := header.Channels
+
+	root, _ := sortnet.NewComparatorNetwork(channels)
+	inputs := set{}
+	for seq := sortnet.BinarySequence(0); seq <= sortnet.SequenceMask(channels); seq++ {
+		inputs[seq] = true
+	}
+	parents := []child{{network: root, set: inputs}}
+
+	// the empty network only sorts a single channel, and is then the sorting network of round 0
+	first := 1
+	if inputs.sorted() {
+		first = 0
+	}
+	for round := first; ; round++ {
+		children := parents
+		if round > 0 {
+			children = derive(header, round, parents)
+		}
+
+		pruned := map[int]*Pruned{}
+		var end *Round
+		for end == nil {
+			record, err := reader.Next()
+			if err == io.EOF {
+				return nil, fmt.Errorf("%w: round %d does not end", ErrInvalid, round)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			switch record := record.(type) {
+			case *Pruned:
+				if record.Round != round {
+					return nil, fmt.Errorf("%w: pruned network of round %d in round %d", ErrInvalid, record.Round, round)
+				}
+				if _, ok := pruned[record.ID]; ok {
+					return nil, fmt.Errorf("%w: round %d: child %d pruned twice", ErrInvalid, round, record.ID)
+				}
+				pruned[record.ID] = record
+			case *Round:
+				end = record
+			default:
+				return nil, fmt.Errorf("%w: unexpected header in round %d", ErrInvalid, round)
+			}
+		}
+		if end.Round != round {
+			return nil, fmt.Errorf("%w: round %d ends as round %d", ErrInvalid, round, end.Round)
+		}
+
+		if len(end.Sorting) > 0 {
+			return finish(reader, header, round, children, pruned, end)
+		}
+
+		survivors, err := checkRound(channels, round, children, pruned, end)
+		if err != nil {
+			return nil, err
+		}
+		parents = survivors
+	}
+}
+
+// derive creates every child of the round, in the order of their ids.
+func derive(header *Header, round int, parents []child) []child {
+	extensions := Extensions(header.Channels, header.Mode, header.AllLayers, round)
+
+	children := make([]child, 0, len(parents)*len(extensions))
+	for _, parent := range parents {
+		for _, extension := range extensions {
+			network := parent.network.DeriveLayers([][]sortnet.Comparator{extension})[0].(*sortnet.ComparatorNetwork)
+			children = append(children, child{
+				network: network,
+				set:     parent.set.apply(header.Channels, extension),
+				parent:  parent.set,
+			})
+		}
+	}
+
+	return children
+}
+
+// checkRound verifies that every child of a round that does not end the search is accounted for, and returns the
+// survivors.
+func checkRound(channels, round int, children []child, pruned map[int]*Pruned, end *Round) ([]child, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: round %d: %s", ErrInvalid, round, fmt.Sprintf(format, args...))
+	}
+
+	surviving := map[int]bool{}
+	var survivors []child
+	for i, id := range end.Survivors {
+		switch {
+		case id < 0 || id >= len(children):
+			return nil, invalid("survivor %d does not exist", id)
+		case i > 0 && id <= end.Survivors[i-1]:
+			return nil, invalid("survivors are not in increasing order")
+		case pruned[id] != nil:
+			return nil, invalid("survivor %d is also pruned", id)
+		}
+		surviving[id] = true
+		survivors = append(survivors, children[id])
+	}
+	if len(survivors) == 0 {
+		return nil, invalid("no network survives")
+	}
+
+	for id, c := range children {
+		if c.set.sorted() {
+			return nil, invalid("child %d sorts, so the search should have ended", id)
+		}
+		if !surviving[id] && pruned[id] == nil && (len(c.set) != len(c.parent) || !c.set.isSubset(c.parent)) {
+			return nil, invalid("child %d is neither a survivor, pruned nor redundant", id)
+		}
+	}
+
+	for id, p := range pruned {
+		if id < 0 || id >= len(children) || p.By < 0 || p.By >= len(children) || p.By == id {
+			return nil, invalid("pruned child %d by %d does not exist", id, p.By)
+		}
+		if !isPermutation(p.Permutation, channels) {
+			return nil, invalid("pruned child %d has no permutation of %d channels", id, channels)
+		}
+		if !children[p.By].set.permute(channels, p.Permutation, p.Reflected).isSubset(children[id].set) {
+			return nil, invalid("child %d is not subsumed by child %d", id, p.By)
+		}
+
+		// the subsuming child may be pruned itself, but the chain must end in a survivor
+		by := p.By
+		for steps := 0; !surviving[by]; steps++ {
+			next, ok := pruned[by]
+			if !ok || steps > len(pruned) {
+				return nil, invalid("child %d is not subsumed by a survivor", id)
+			}
+			by = next.By
+		}
+	}
+
+	return survivors, nil
+}
+
+// finish checks the last round, which lists its sorting networks, and that nothing follows it.
+func finish(reader *Reader, header *Header, round int, children []child, pruned map[int]*Pruned, end *Round) (*Result, error) {
+	if len(pruned) > 0 || len(end.Survivors) > 0 {
+		return nil, fmt.Errorf("%w: round %d lists sorting networks but also prunes", ErrInvalid, round)
+	}
+
+	result := &Result{Channels: header.Channels, Mode: header.Mode, Bound: round}
+	for _, id := range end.Sorting {
+		if id < 0 || id >= len(children) || !children[id].set.sorted() {
+			return nil, fmt.Errorf("%w: round %d: child %d is not a sorting network", ErrInvalid, round, id)
+		}
+		result.Sorting = append(result.Sorting, children[id].network)
+	}
+
+	if _, err := reader.Next(); err != io.EOF {
+		return nil, fmt.Errorf("%w: records after the last round", ErrInvalid)
+	}
+	return result, nil
+}
+
+func isPermutation(permutation sortnet.PermutationMap, channels int) bool {
+	if len(permutation) != channels {
+		return false
+	}
+
+	seen := make([]bool, channels)
+	for _, channel := range permutation {
+		if channel < 0 || channel >= channels || seen[channel] {
+			return false
+		}
+		seen[channel] = true
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+package cert_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/cert"
+	"github.com/andersfylling/go-sortnet/sortnet/search"
+)
+
+func certificate(t *testing.T, config search.Config) (*search.Result, []string) {
+	var buf bytes.Buffer
+	config.Certificate = &buf
+	engine, err := search.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result, strings.SplitAfter(buf.String(), "\n")
+}
+
+func TestCheck(t *testing.T) {
+	configs := []search.Config{
+		{Channels: 1},
+		{Channels: 5},
+		{Channels: 5, Reflection: true, Workers: 4},
+		{Channels: 5, Mode: search.DepthMode},
+		{Channels: 6, Mode: search.DepthMode, Reflection: true},
+	}
+	for _, config := range configs {
+		found, lines := certificate(t, config)
+
+		result, err := cert.Check(strings.NewReader(strings.Join(lines, "")))
+		if err != nil {
+			t.Fatalf("%+v: %v", config, err)
+		}
+		bound := found.Comparators
+		if config.Mode == search.DepthMode {
+			bound = found.Depth
+		}
+		if result.Bound != bound || len(result.Sorting) != len(found.Networks) {
+			t.Errorf("%+v: expected a bound of %d with %d networks, got %d with %d",
+				config, bound, len(found.Networks), result.Bound, len(result.Sorting))
+		}
+		for _, network := range result.Sorting {
+			if ok, _ := sortnet.IsSortingNetwork(network, config.Channels); !ok {
+				t.Errorf("%+v: expected %s to sort", config, network)
+			}
+		}
+	}
+}
+
+// interrupted is cancelled after Err has been called the given number of times.
+type interrupted struct {
+	context.Context
+	calls int
+}
+
+func (c *interrupted) Err() error {
+	if c.calls--; c.calls < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestCheckResumed(t *testing.T) {
+	dir := t.TempDir()
+	for _, interrupt := range []int{2, 15, 40} {
+		file, err := os.Create(filepath.Join(dir, fmt.Sprintf("cert%d", interrupt)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+
+		config := search.Config{
+			Channels:        5,
+			Reflection:      true,
+			Certificate:     file,
+			CheckpointPath:  filepath.Join(dir, fmt.Sprintf("checkpoint%d", interrupt)),
+			CheckpointEvery: 5,
+		}
+		engine, _ := search.New(config)
+		if _, err = engine.Run(&interrupted{Context: context.Background(), calls: interrupt}); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the search to be cancelled after %d checks, got %v", interrupt, err)
+		}
+		// records written after the checkpoint are dropped when resuming
+		if _, err = file.WriteString(`{"type":"round","round":99}` + "\n"); err != nil {
+			t.Fatal(err)
+		}
+
+		config.Resume = true
+		engine, err = search.New(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = engine.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		if result, err := cert.Check(file); err != nil || result.Bound != 9 {
+			t.Errorf("interrupted after %d checks: expected a bound of 9, got %+v, %v", interrupt, result, err)
+		}
+	}
+}
+
+func TestCheckTampered(t *testing.T) {
+	_, lines := certificate(t, search.Config{Channels: 5, Reflection: true})
+
+	var pruned, round int
+	for i, line := range lines {
+		switch {
+		case pruned == 0 && strings.Contains(line, `"pruned"`):
+			pruned = i
+		case pruned > 0 && round == 0 && strings.Contains(line, `"survivors"`):
+			round = i
+		}
+	}
+
+	tampered := map[string][]string{
+		"permutation": replace(lines, pruned, strings.Replace(lines[pruned], `"permutation":[`, `"permutation":[9,`, 1)),
+		"missing":     append(append([]string{}, lines[:pruned]...), lines[pruned+1:]...),
+		"survivor":    replace(lines, round, strings.Replace(lines[round], `"survivors":[`, `"survivors":[100000,`, 1)),
+		"truncated":   lines[:len(lines)-2],
+	}
+	for name, lines := range tampered {
+		if _, err := cert.Check(strings.NewReader(strings.Join(lines, ""))); !errors.Is(err, cert.ErrInvalid) {
+			t.Errorf("%s: expected an invalid certificate, got %v", name, err)
+		}
+	}
+}
+
+func replace(lines []string, i int, line string) []string {
+	replaced := append([]string{}, lines...)
+	replaced[i] = line
+	return replaced
+}
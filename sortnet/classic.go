@@ -0,0 +1,175 @@
+package sortnet
+
+// Classic sorting network constructions. They are far from optimal for most channel counts, but are useful as
+// reference networks, upper bounds and benchmark inputs. The constructions are described using the literature
+// convention of comparing channel i < j, see pair.
+//
+// Every constructor panics when channels is outside of the range [0, MaxChannels].
+
+// pair creates the comparator between channel i and j, where i < j receives the minimum.
+func pair(i, j int) Comparator {
+	return Comparator{From: j, To: i}
+}
+
+func mustNewComparatorNetwork(channels int, comparators []Comparator) *ComparatorNetwork {
+	network, err := NewComparatorNetwork(channels, comparators...)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p *= 2
+	}
+	return p
+}
+
+// truncate removes the comparators using channels at or above the given channel count. A network for a larger
+// power of two is turned into a network for the given channels this way, by thinking of the removed channels as
+// holding the maximum value which never moves.
+func truncate(channels int, comparators []Comparator) []Comparator {
+	var kept []Comparator
+	for _, comparator := range comparators {
+		if comparator.From < channels {
+			kept = append(kept, comparator)
+		}
+	}
+	return kept
+}
+
+// NewBatcherOddEvenMergeSort creates Batcher's odd-even mergesort network.
+func NewBatcherOddEvenMergeSort(channels int) *ComparatorNetwork {
+	var comparators []Comparator
+	for p := 1; p < channels; p *= 2 {
+		for k := p; k >= 1; k /= 2 {
+			for j := k % p; j+k < channels; j += 2 * k {
+				for i := 0; i < k && i+j+k < channels; i++ {
+					if (i+j)/(2*p) == (i+j+k)/(2*p) {
+						comparators = append(comparators, pair(i+j, i+j+k))
+					}
+				}
+			}
+		}
+	}
+
+	return mustNewComparatorNetwork(channels, comparators)
+}
+
+// NewBitonicSort creates Batcher's bitonic sorter. The first merge step of every block compares mirrored channels,
+// such that every comparator sends the minimum to the lower channel.
+func NewBitonicSort(channels int) *ComparatorNetwork {
+	n := nextPowerOfTwo(channels)
+
+	var comparators []Comparator
+	for k := 2; k <= n; k *= 2 {
+		for j := k / 2; j > 0; j /= 2 {
+			for i := 0; i < n; i++ {
+				partner := i ^ j
+				if j == k/2 {
+					partner = i ^ (k - 1)
+				}
+
+				if partner > i {
+					comparators = append(comparators, pair(i, partner))
+				}
+			}
+		}
+	}
+
+	return mustNewComparatorNetwork(channels, truncate(channels, comparators))
+}
+
+// NewPairwiseSort creates Parberry's pairwise sorting network.
+func NewPairwiseSort(channels int) *ComparatorNetwork {
+	n := nextPowerOfTwo(channels)
+
+	// compare channels pairwise in blocks of growing distance
+	var comparators []Comparator
+	a := 1
+	for ; a < n; a *= 2 {
+		for b, c := a, 0; b < n; {
+			comparators = append(comparators, pair(b-a, b))
+
+			b++
+			c = (c + 1) % a
+			if c == 0 {
+				b += a
+			}
+		}
+	}
+
+	// merge the blocks in reverse order
+	for a, e := a/4, 1; a > 0; a, e = a/2, 2*e+1 {
+		for d := e; d > 0; d /= 2 {
+			for b, c := (d+1)*a, 0; b < n; {
+				comparators = append(comparators, pair(b-d*a, b))
+
+				b++
+				c = (c + 1) % a
+				if c == 0 {
+					b += a
+				}
+			}
+		}
+	}
+
+	return mustNewComparatorNetwork(channels, truncate(channels, comparators))
+}
+
+// NewBoseNelsonSort creates the recursive network by Bose and Nelson.
+func NewBoseNelsonSort(channels int) *ComparatorNetwork {
+	var comparators []Comparator
+
+	var merge func(i, x, j, y int)
+	merge = func(i, x, j, y int) {
+		switch {
+		case x == 1 && y == 1:
+			comparators = append(comparators, pair(i, j))
+		case x == 1 && y == 2:
+			comparators = append(comparators, pair(i, j+1), pair(i, j))
+		case x == 2 && y == 1:
+			comparators = append(comparators, pair(i, j), pair(i+1, j))
+		default:
+			a := x / 2
+			b := (y + 1) / 2
+			if x%2 == 1 {
+				b = y / 2
+			}
+
+			merge(i, a, j, b)
+			merge(i+a, x-a, j+b, y-b)
+			merge(i+a, x-a, j, b)
+		}
+	}
+
+	var sort func(i, n int)
+	sort = func(i, n int) {
+		if n < 2 {
+			return
+		}
+
+		m := n / 2
+		sort(i, m)
+		sort(i+m, n-m)
+		merge(i, m, i+m, n-m)
+	}
+	sort(0, channels)
+
+	return mustNewComparatorNetwork(channels, comparators)
+}
+
+// NewOddEvenTranspositionSort creates the odd-even transposition sort, which alternates between comparing the even
+// and odd pairs of neighbouring channels.
+func NewOddEvenTranspositionSort(channels int) *ComparatorNetwork {
+	var comparators []Comparator
+	for round := 0; round < channels; round++ {
+		for i := round % 2; i+1 < channels; i += 2 {
+			comparators = append(comparators, pair(i, i+1))
+		}
+	}
+
+	return mustNewComparatorNetwork(channels, comparators)
+}
@@ -0,0 +1,43 @@
+package sortnet
+
+import "testing"
+
+func TestClassicNetworks(t *testing.T) {
+	constructions := []struct {
+		name string
+		new  func(channels int) *ComparatorNetwork
+		size int // for 16 channels
+	}{
+		{"batcher", NewBatcherOddEvenMergeSort, 63},
+		{"bitonic", NewBitonicSort, 80},
+		{"pairwise", NewPairwiseSort, 63},
+		{"bose-nelson", NewBoseNelsonSort, 65},
+		{"odd-even transposition", NewOddEvenTranspositionSort, 120},
+	}
+
+	for _, construction := range constructions {
+		for channels := 0; channels <= 16 && channels <= MaxChannels; channels++ {
+			network := construction.new(channels)
+			if network.Channels() != channels {
+				t.Errorf("%s: expected %d channels, got %d", construction.name, channels, network.Channels())
+			}
+			if ok, counterexample := IsSortingNetwork(network, channels); !ok {
+				t.Errorf("%s: not sorting for %d channels, counterexample %+v", construction.name, channels, counterexample)
+			}
+		}
+
+		if size := construction.new(16).Len(); size != construction.size {
+			t.Errorf("%s: expected %d comparators for 16 channels, got %d", construction.name, construction.size, size)
+		}
+	}
+}
+
+func BenchmarkTransform(b *testing.B) {
+	network := NewBatcherOddEvenMergeSort(MaxChannels)
+	mask := SequenceMask(MaxChannels)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		network.Transform(BinarySequence(i) & mask)
+	}
+}
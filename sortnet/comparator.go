@@ -1,5 +1,7 @@
 package sortnet
 
+import "fmt"
+
 var comparatorsCache = map[int][]Comparator{}
 
 func AllComparatorCombinations(sequenceSize int) []Comparator {
@@ -24,3 +26,19 @@ type Comparator struct {
 	From int
 	To   int
 }
+
+// mask returns a binary sequence with the bits of both channels set.
+func (c Comparator) mask() BinarySequence {
+	return BinarySequence(1)<<c.From | BinarySequence(1)<<c.To
+}
+
+func (c Comparator) validate(channels int) error {
+	if c.From <= c.To {
+		return fmt.Errorf("comparator must move from a higher to a lower channel, got from %d to %d", c.From, c.To)
+	}
+	if c.To < 0 || c.From >= channels {
+		return fmt.Errorf("comparator from %d to %d is outside of %d channels", c.From, c.To, channels)
+	}
+
+	return nil
+}
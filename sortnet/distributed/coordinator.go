@@ -0,0 +1,194 @@
+package distributed
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/search"
+	"golang.org/x/sync/errgroup"
+)
+
+// Coordinator runs the subsumption tests of a search on the connected workers.
+type Coordinator struct {
+	// Generator names the permutation generator of the workers in sortnet.SubsetPermutationGenerators. Defaults to
+	// "subset", the default of search.Config.
+	Generator string
+
+	listener net.Listener
+	workers  []*conn
+
+	// sets are the output sets of the current round, where the subsuming sets are taken from
+	sets []sortnet.OutputSet
+
+	// pruned holds the sets pruned by the previous check, which the workers are told about with the next one.
+	pruned []int
+}
+
+// Listen creates a coordinator accepting workers on an address such as "unix:/tmp/sortnet.sock" or "tcp::7000".
+func Listen(address string) (*Coordinator, error) {
+	network, addr, err := splitAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen(network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Coordinator{listener: listener}, nil
+}
+
+// Addr returns the address workers connect to, in the format accepted by Work.
+func (c *Coordinator) Addr() string {
+	return c.listener.Addr().Network() + ":" + c.listener.Addr().String()
+}
+
+// Accept waits until the given number of workers have connected.
+func (c *Coordinator) Accept(ctx context.Context, workers int) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.listener.Close()
+		case <-done:
+		}
+	}()
+
+	for len(c.workers) < workers {
+		worker, err := c.listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		c.workers = append(c.workers, newConn(worker))
+	}
+
+	return nil
+}
+
+// Close disconnects the workers, which makes them return, and stops accepting new ones.
+func (c *Coordinator) Close() error {
+	for _, worker := range c.workers {
+		worker.Close()
+	}
+	return c.listener.Close()
+}
+
+func (c *Coordinator) Round(ctx context.Context, channels int, reflection bool, sets []sortnet.OutputSet) error {
+	if len(c.workers) == 0 {
+		return fmt.Errorf("no workers connected")
+	}
+	generator := c.Generator
+	if generator == "" {
+		generator = "subset"
+	}
+	if _, ok := sortnet.SubsetPermutationGenerators[generator]; !ok {
+		return fmt.Errorf("unknown permutation generator %q", generator)
+	}
+
+	rounds := make([]roundRequest, len(c.workers))
+	for shard := range rounds {
+		rounds[shard] = roundRequest{
+			Channels:   channels,
+			Shard:      shard,
+			Shards:     len(c.workers),
+			Sets:       make([][]byte, len(sets)),
+			Reflection: reflection,
+			Generator:  generator,
+		}
+	}
+	for id, set := range sets {
+		if set == nil {
+			continue
+		}
+
+		var err error
+		if rounds[id%len(rounds)].Sets[id], err = set.MarshalBinary(); err != nil {
+			return fmt.Errorf("encoding output set %d: %w", id, err)
+		}
+	}
+	c.sets = sets
+	c.pruned = nil
+
+	_, err := c.call(ctx, func(shard int) request {
+		return request{Round: &rounds[shard]}
+	})
+	return err
+}
+
+func (c *Coordinator) Subsumed(ctx context.Context, id int) ([]search.Subsumption, error) {
+	if id < 0 || id >= len(c.sets) || c.sets[id] == nil {
+		return nil, fmt.Errorf("output set %d is not part of the round", id)
+	}
+	set, err := c.sets[id].MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("encoding output set %d: %w", id, err)
+	}
+
+	check := &checkRequest{ID: id, Set: set, Pruned: c.pruned}
+	responses, err := c.call(ctx, func(int) request {
+		return request{Check: check}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var subsumed []search.Subsumption
+	for _, r := range responses {
+		subsumed = append(subsumed, r.Subsumed...)
+	}
+	sort.Slice(subsumed, func(i, j int) bool { return subsumed[i].ID < subsumed[j].ID })
+
+	c.pruned = make([]int, len(subsumed))
+	for i, sub := range subsumed {
+		c.pruned[i] = sub.ID
+	}
+	return subsumed, nil
+}
+
+// call sends a request to every worker and waits for all responses. A cancelled context interrupts the connections.
+func (c *Coordinator) call(ctx context.Context, newRequest func(shard int) request) ([]response, error) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			for _, worker := range c.workers {
+				worker.SetDeadline(time.Now())
+			}
+		case <-done:
+		}
+	}()
+
+	responses := make([]response, len(c.workers))
+	var g errgroup.Group
+	for shard, worker := range c.workers {
+		shard, worker := shard, worker
+		g.Go(func() error {
+			if err := worker.enc.Encode(newRequest(shard)); err != nil {
+				return fmt.Errorf("worker %d: %w", shard, err)
+			}
+			if err := worker.dec.Decode(&responses[shard]); err != nil {
+				return fmt.Errorf("worker %d: %w", shard, err)
+			}
+			if responses[shard].Err != "" {
+				return fmt.Errorf("worker %d: %s", shard, responses[shard].Err)
+			}
+			return nil
+		})
+	}
+
+	err := g.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return responses, err
+}
@@ -0,0 +1,132 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/andersfylling/go-sortnet/sortnet/cert"
+	"github.com/andersfylling/go-sortnet/sortnet/outputset"
+	"github.com/andersfylling/go-sortnet/sortnet/search"
+)
+
+// TestMain turns the test binary into a worker process when started by TestDistributedSearch.
+func TestMain(m *testing.M) {
+	if address := os.Getenv("SORTNET_TEST_COORDINATOR"); address != "" {
+		if err := Work(context.Background(), address, WorkerConfig{Workers: 2}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestDistributedSearch(t *testing.T) {
+	const workers = 3
+
+	coordinator, err := Listen("unix:" + filepath.Join(t.TempDir(), "coordinator.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer coordinator.Close()
+
+	var processes []*exec.Cmd
+	for i := 0; i < workers; i++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^$")
+		cmd.Env = append(os.Environ(), "SORTNET_TEST_COORDINATOR="+coordinator.Addr())
+		cmd.Stderr = os.Stderr
+		if err = cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		processes = append(processes, cmd)
+	}
+
+	if err = coordinator.Accept(context.Background(), workers); err != nil {
+		t.Fatal(err)
+	}
+
+	for channels := 3; channels <= 6; channels++ {
+		// the workers follow the reflection setting of the search and the generator of the coordinator
+		reflection := channels%2 == 0
+		coordinator.Generator = map[bool]string{true: "bitmap", false: "subset"}[reflection]
+
+		local, _ := search.New(search.Config{Channels: channels, Reflection: reflection})
+		expected, err := local.Run(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var certificate bytes.Buffer
+		distributed, _ := search.New(search.Config{Channels: channels, Reflection: reflection, Pruner: coordinator, Certificate: &certificate})
+		result, err := distributed.Run(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = cert.Check(&certificate); err != nil {
+			t.Errorf("%d channels: %v", channels, err)
+		}
+
+		if fmt.Sprint(result.Networks) != fmt.Sprint(expected.Networks) {
+			t.Errorf("%d channels: expected the same networks as a local search", channels)
+		}
+		for i := range expected.Rounds {
+			if result.Rounds[i].Pruned != expected.Rounds[i].Pruned {
+				t.Errorf("%d channels: expected %d pruned networks in round %d, got %d",
+					channels, expected.Rounds[i].Pruned, i+1, result.Rounds[i].Pruned)
+			}
+		}
+	}
+
+	coordinator.Generator = "unknown"
+	distributed, _ := search.New(search.Config{Channels: 4, Pruner: coordinator})
+	if _, err = distributed.Run(context.Background()); err == nil {
+		t.Error("expected an unknown permutation generator to be rejected")
+	}
+
+	// the workers return once the coordinator disconnects
+	coordinator.Close()
+	for _, cmd := range processes {
+		if err = cmd.Wait(); err != nil {
+			t.Errorf("worker failed: %s", err)
+		}
+	}
+}
+
+func TestWorkerShard(t *testing.T) {
+	w := &worker{config: WorkerConfig{Workers: 2}}
+	w.config.setDefaults()
+	if _, err := w.check(checkRequest{ID: 3}); err == nil {
+		t.Error("expected a check before the first round to fail")
+	}
+
+	set, _ := outputset.NewPartitionedOrdered(4).MarshalBinary()
+	round := roundRequest{Channels: 4, Shard: 1, Shards: 2, Sets: [][]byte{nil, set, nil, set}, Generator: "subset"}
+	if err := w.startRound(round); err != nil {
+		t.Fatal(err)
+	}
+	if w.sets[0] != nil || w.sets[1] == nil || w.sets[3] == nil {
+		t.Error("expected the worker to only hold the sets of its shard")
+	}
+
+	for _, check := range []checkRequest{{ID: 4, Set: set}, {ID: 0, Set: set, Pruned: []int{-1}}, {ID: 0, Set: []byte{0}}} {
+		if _, err := w.check(check); err == nil {
+			t.Errorf("expected the check %+v to fail", check)
+		}
+	}
+	if subsumed, err := w.check(checkRequest{ID: 0, Set: set}); err != nil || len(subsumed) != 2 {
+		t.Errorf("expected the sets of the shard to be subsumed by an equal set, got %v %v", subsumed, err)
+	}
+
+	if err := w.startRound(roundRequest{Channels: 4, Shards: 1, Sets: [][]byte{{0}}, Generator: "subset"}); err == nil {
+		t.Fatal("expected an invalid set to fail the round")
+	}
+	if _, err := w.check(checkRequest{ID: 0, Set: set}); err == nil {
+		t.Error("expected a check after a failed round to fail")
+	}
+}
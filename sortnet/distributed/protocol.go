@@ -0,0 +1,73 @@
+// Package distributed spreads the subsumption tests of a search over several processes. A Coordinator implements
+// search.Pruner and holds the connections to the workers, which are started with Work and connect to it over TCP or a
+// Unix socket. Every worker receives its share of the output sets of a round once, and then tests them against every
+// subsuming set the engine asks for, which is sent along with the request.
+//
+//	coordinator:  sortnet search -channels 9 -coordinator unix:/tmp/sortnet.sock -remote-workers 4
+//	workers:      sortnet worker -connect unix:/tmp/sortnet.sock
+package distributed
+
+import (
+	"encoding/gob"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/andersfylling/go-sortnet/sortnet/search"
+)
+
+// request is sent by the coordinator, with exactly one of the fields set.
+type request struct {
+	Round *roundRequest
+	Check *checkRequest
+}
+
+// roundRequest hands the output sets of a round to a worker, which tests the sets whose id modulo Shards is Shard.
+// Sets holds an encoded set per id, which is empty for pruned sets and the sets of other shards. Reflection and the
+// name of the permutation generator are the settings of the search, which every worker must test with.
+type roundRequest struct {
+	Channels   int
+	Shard      int
+	Shards     int
+	Sets       [][]byte
+	Reflection bool
+	Generator  string
+}
+
+// checkRequest asks for the sets subsumed by the set with the given id, whose encoding is Set. Pruned holds the sets
+// pruned by the previous check.
+type checkRequest struct {
+	ID     int
+	Set    []byte
+	Pruned []int
+}
+
+type response struct {
+	Subsumed []search.Subsumption
+	Err      string
+}
+
+type conn struct {
+	net.Conn
+	enc *gob.Encoder
+	dec *gob.Decoder
+}
+
+func newConn(c net.Conn) *conn {
+	return &conn{
+		Conn: c,
+		enc:  gob.NewEncoder(c),
+		dec:  gob.NewDecoder(c),
+	}
+}
+
+// splitAddress splits addresses such as "unix:/tmp/sortnet.sock" and "tcp:localhost:7000" into the network and the
+// address expected by the net package.
+func splitAddress(address string) (string, string, error) {
+	network, addr, ok := strings.Cut(address, ":")
+	if !ok || (network != "tcp" && network != "unix") {
+		return "", "", fmt.Errorf("address %q must start with tcp: or unix:", address)
+	}
+
+	return network, addr, nil
+}
@@ -0,0 +1,198 @@
+package distributed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/outputset"
+	"github.com/andersfylling/go-sortnet/sortnet/search"
+)
+
+// WorkerConfig holds the settings of a worker. Zero values are replaced by the same defaults as search.Config. The
+// settings deciding the outcome of a subsumption test, reflection and the permutation generator, are sent by the
+// coordinator every round.
+type WorkerConfig struct {
+	NewSet outputset.NewSet
+
+	// Workers is the number of goroutines testing the sets of this worker.
+	Workers int
+}
+
+func (c *WorkerConfig) setDefaults() {
+	if c.NewSet == nil {
+		c.NewSet = outputset.NewPartitionedOrdered
+	}
+	if c.Workers <= 0 {
+		c.Workers = runtime.NumCPU()
+	}
+}
+
+// Work connects to the coordinator at the given address and answers its requests until the coordinator closes the
+// connection, or the context is cancelled.
+func Work(ctx context.Context, address string, config WorkerConfig) error {
+	config.setDefaults()
+
+	network, addr, err := splitAddress(address)
+	if err != nil {
+		return err
+	}
+	var dialer net.Dialer
+	c, err := dialer.DialContext(ctx, network, addr)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-done:
+		}
+	}()
+
+	w := &worker{config: config, conn: newConn(c)}
+	err = w.serve()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+type worker struct {
+	config WorkerConfig
+	conn   *conn
+
+	round    roundRequest
+	sets     []sortnet.OutputSet
+	generate sortnet.GenerateSubsetPermutationsFunc
+	started  bool
+}
+
+func (w *worker) serve() error {
+	for {
+		var req request
+		if err := w.conn.dec.Decode(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		var resp response
+		switch {
+		case req.Round != nil:
+			if err := w.startRound(*req.Round); err != nil {
+				resp.Err = err.Error()
+			}
+		case req.Check != nil:
+			var err error
+			if resp.Subsumed, err = w.check(*req.Check); err != nil {
+				resp.Err = err.Error()
+			}
+		default:
+			resp.Err = "empty request"
+		}
+
+		if err := w.conn.enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// startRound decodes the sets of this shard. The other sets are nil, as are all sets when decoding fails, such that
+// checks fail until the next round starts.
+func (w *worker) startRound(round roundRequest) error {
+	w.round, w.sets, w.started = round, nil, false
+	w.round.Sets = nil
+
+	var ok bool
+	if w.generate, ok = sortnet.SubsetPermutationGenerators[round.Generator]; !ok {
+		return fmt.Errorf("unknown permutation generator %q", round.Generator)
+	}
+
+	empty := w.config.NewSet(round.Channels)
+	sets := make([]sortnet.OutputSet, len(round.Sets))
+	for id, data := range round.Sets {
+		if len(data) == 0 {
+			continue
+		}
+
+		set := empty.NewEmpty()
+		if err := set.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("output set %d: %w", id, err)
+		}
+		sets[id] = set
+	}
+
+	w.sets, w.started = sets, true
+	return nil
+}
+
+// check returns the sets of this shard that are subsumed by the set of the request, with their witnesses.
+func (w *worker) check(check checkRequest) ([]search.Subsumption, error) {
+	if !w.started {
+		return nil, errors.New("no round started")
+	}
+	if check.ID < 0 || check.ID >= len(w.sets) {
+		return nil, fmt.Errorf("output set %d is not part of the round", check.ID)
+	}
+	for _, id := range check.Pruned {
+		if id < 0 || id >= len(w.sets) {
+			return nil, fmt.Errorf("pruned output set %d is not part of the round", id)
+		}
+		w.sets[id] = nil
+	}
+
+	current := w.config.NewSet(w.round.Channels).NewEmpty()
+	if err := current.UnmarshalBinary(check.Set); err != nil {
+		return nil, fmt.Errorf("output set %d: %w", check.ID, err)
+	}
+	subsumers := []sortnet.OutputSet{current}
+	if w.round.Reflection {
+		subsumers = append(subsumers, sortnet.ReflectOutputSet(current, w.round.Channels))
+	}
+
+	work := make(chan int, w.config.Workers)
+	go func() {
+		defer close(work)
+		for id := w.round.Shard; id < len(w.sets); id += w.round.Shards {
+			if id != check.ID && w.sets[id] != nil {
+				work <- id
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var subsumed []search.Subsumption
+	for i := 0; i < w.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range work {
+				for reflected, subsumer := range subsumers {
+					if permutation, ok := search.Witness(w.round.Channels, subsumer, w.sets[id], w.generate); ok {
+						mu.Lock()
+						subsumed = append(subsumed, search.Subsumption{ID: id, Permutation: permutation, Reflected: reflected > 0})
+						mu.Unlock()
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(subsumed, func(i, j int) bool { return subsumed[i].ID < subsumed[j].ID })
+	return subsumed, nil
+}
@@ -0,0 +1,82 @@
+// Package known holds the best-known sorting networks for 2 to 16 channels, ordered either by the number of
+// comparators or by the number of layers. Searches use them to validate their results and to stop once a proven
+// optimum is reached.
+package known
+
+import (
+	"fmt"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+// Entry is the best-known network for a channel count. Proven tells whether the bound is proven to be optimal, and
+// not just the best upper bound found so far.
+type Entry struct {
+	Network *sortnet.ComparatorNetwork
+	Size    int
+	Depth   int
+	Proven  bool
+}
+
+type entry struct {
+	channels int
+	size     int
+	depth    int
+	proven   bool
+	text     string
+}
+
+var (
+	sizeOptimal  = parse(bySize)
+	depthOptimal = parse(byDepth)
+)
+
+func parse(entries []entry) map[int]Entry {
+	parsed := make(map[int]Entry, len(entries))
+	for _, e := range entries {
+		if e.channels > sortnet.MaxChannels {
+			continue
+		}
+
+		comparators, err := sortnet.ParseComparators(e.text)
+		if err != nil {
+			panic(fmt.Sprintf("known network for %d channels: %s", e.channels, err))
+		}
+		network, err := sortnet.NewComparatorNetwork(e.channels, comparators...)
+		if err != nil {
+			panic(fmt.Sprintf("known network for %d channels: %s", e.channels, err))
+		}
+
+		parsed[e.channels] = Entry{
+			Network: network,
+			Size:    e.size,
+			Depth:   e.depth,
+			Proven:  e.proven,
+		}
+	}
+
+	return parsed
+}
+
+// BestBySize returns the network with the fewest comparators known for the channel count. For equal sizes the
+// network with the lowest depth is picked.
+func BestBySize(channels int) (Entry, bool) {
+	return lookup(sizeOptimal, channels)
+}
+
+// BestByDepth returns the network with the fewest layers known for the channel count. For equal depths the network
+// with the fewest comparators is picked.
+func BestByDepth(channels int) (Entry, bool) {
+	return lookup(depthOptimal, channels)
+}
+
+// lookup returns the entry with a copy of its network, such that callers can not change the catalogue.
+func lookup(entries map[int]Entry, channels int) (Entry, bool) {
+	e, ok := entries[channels]
+	if !ok {
+		return e, false
+	}
+
+	e.Network, _ = sortnet.NewComparatorNetwork(channels, e.Network.Comparators()...)
+	return e, true
+}
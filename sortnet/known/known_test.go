@@ -0,0 +1,56 @@
+package known
+
+import (
+	"testing"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+func TestKnownNetworks(t *testing.T) {
+	lookups := map[string]func(channels int) (Entry, bool){
+		"size":  BestBySize,
+		"depth": BestByDepth,
+	}
+
+	for name, lookup := range lookups {
+		for channels := 2; channels <= 16 && channels <= sortnet.MaxChannels; channels++ {
+			e, ok := lookup(channels)
+			if !ok {
+				t.Errorf("%s: missing network for %d channels", name, channels)
+				continue
+			}
+
+			if ok, counterexample := sortnet.IsSortingNetwork(e.Network, channels); !ok {
+				t.Errorf("%s: not sorting for %d channels, counterexample %+v", name, channels, counterexample)
+			}
+			if e.Network.Len() != e.Size {
+				t.Errorf("%s: expected %d comparators for %d channels, got %d", name, e.Size, channels, e.Network.Len())
+			}
+			if d := e.Network.Depth(); d != e.Depth {
+				t.Errorf("%s: expected depth %d for %d channels, got %d", name, e.Depth, channels, d)
+			}
+		}
+
+		if _, ok := lookup(17); ok {
+			t.Errorf("%s: unexpected network for 17 channels", name)
+		}
+	}
+
+	if e, _ := BestBySize(16); e.Size != 60 || e.Proven {
+		t.Errorf("expected Green's unproven 60 comparator network for 16 channels, got %d", e.Size)
+	}
+	if e, _ := BestByDepth(16); e.Depth != 9 || !e.Proven {
+		t.Errorf("expected a proven depth of 9 for 16 channels, got %d", e.Depth)
+	}
+}
+
+func TestKnownNetworksCopied(t *testing.T) {
+	e, _ := BestBySize(4)
+	if err := e.Network.UnmarshalText([]byte("[(0,1)]")); err != nil {
+		t.Fatal(err)
+	}
+
+	if e, _ = BestBySize(4); e.Network.Len() != e.Size {
+		t.Errorf("expected the catalogue to keep %d comparators, got %d", e.Size, e.Network.Len())
+	}
+}
@@ -0,0 +1,73 @@
+package known
+
+// The networks are collected from Knuth, The Art of Computer Programming Vol. 3, and the results of Codish et al.,
+// Harder, and Bundala and Závodný who proved the optimal sizes for up to 12 channels and the optimal depths for up to
+// 16 channels. The depth-optimal networks for 10 and 12 to 16 channels were found with a SAT encoding.
+// Every network is written in the layered text notation of sortnet.ParseComparatorNetwork.
+
+// columns: channels, size, depth, proven, network
+var bySize = []entry{
+	{2, 1, 1, true,
+		`[[(0,1)]]`},
+	{3, 3, 3, true,
+		`[[(0,2)],[(0,1)],[(1,2)]]`},
+	{4, 5, 3, true,
+		`[[(0,2),(1,3)],[(0,1),(2,3)],[(1,2)]]`},
+	{5, 9, 5, true,
+		`[[(0,3),(1,4)],[(0,2),(1,3)],[(0,1),(2,4)],[(1,2),(3,4)],[(2,3)]]`},
+	{6, 12, 5, true,
+		`[[(0,5),(1,3),(2,4)],[(1,2),(3,4)],[(0,3),(2,5)],[(0,1),(2,3),(4,5)],[(1,2),(3,4)]]`},
+	{7, 16, 6, true,
+		`[[(0,6),(2,3),(4,5)],[(0,2),(1,4),(3,6)],[(0,1),(2,5),(3,4)],[(1,2),(4,6)],[(2,3),(4,5)],[(1,2),(3,4),(5,6)]]`},
+	{8, 19, 6, true,
+		`[[(0,2),(1,3),(4,6),(5,7)],[(0,4),(1,5),(2,6),(3,7)],[(0,1),(2,3),(4,5),(6,7)],[(2,4),(3,5)],[(1,4),(3,6)],[(1,2),(3,4),(5,6)]]`},
+	{9, 25, 7, true,
+		`[[(0,3),(1,7),(2,5),(4,8)],[(0,7),(2,4),(3,8),(5,6)],[(0,2),(1,3),(4,5),(7,8)],[(1,4),(3,6),(5,7)],[(0,1),(2,4),(3,5),(6,8)],[(2,3),(4,5),(6,7)],[(1,2),(3,4),(5,6)]]`},
+	{10, 29, 8, true,
+		`[[(0,8),(1,9),(2,7),(3,5),(4,6)],[(0,2),(1,4),(5,8),(7,9)],[(0,3),(2,4),(5,7),(6,9)],[(0,1),(3,6),(8,9)],[(1,5),(2,3),(4,8),(6,7)],[(1,2),(3,5),(4,6),(7,8)],[(2,3),(4,5),(6,7)],[(3,4),(5,6)]]`},
+	{11, 35, 8, true,
+		`[[(0,9),(1,6),(2,4),(3,7),(5,8)],[(0,1),(3,5),(4,10),(6,9),(7,8)],[(1,3),(2,5),(4,7),(8,10)],[(0,4),(1,2),(3,7),(5,9),(6,8)],[(0,1),(2,6),(4,5),(7,8),(9,10)],[(2,4),(3,6),(5,7),(8,9)],[(1,2),(3,4),(5,6),(7,8)],[(2,3),(4,5),(6,7)]]`},
+	{12, 39, 9, true,
+		`[[(0,8),(1,7),(2,6),(3,11),(4,10),(5,9)],[(0,1),(2,5),(3,4),(6,9),(7,8),(10,11)],[(0,2),(1,6),(5,10),(9,11)],[(0,3),(1,2),(4,6),(5,7),(8,11),(9,10)],[(1,4),(3,5),(6,8),(7,10)],[(1,3),(2,5),(6,9),(8,10)],[(2,3),(4,5),(6,7),(8,9)],[(4,6),(5,7)],[(3,4),(5,6),(7,8)]]`},
+	{13, 45, 10, false,
+		`[[(0,12),(1,10),(2,9),(3,7),(5,11),(6,8)],[(1,6),(2,3),(4,11),(7,9),(8,10)],[(0,4),(1,2),(3,6),(7,8),(9,10),(11,12)],[(4,6),(5,9),(8,11),(10,12)],[(0,5),(3,8),(4,7),(6,11),(9,10)],[(0,1),(2,5),(6,9),(7,8),(10,11)],[(1,3),(2,4),(5,6),(9,10)],[(1,2),(3,4),(5,7),(6,8)],[(2,3),(4,5),(6,7),(8,9)],[(3,4),(5,6)]]`},
+	{14, 51, 10, false,
+		`[[(0,1),(2,3),(4,5),(6,7),(8,9),(10,11),(12,13)],[(0,2),(1,3),(4,8),(5,9),(10,12),(11,13)],[(0,4),(1,2),(3,7),(5,8),(6,10),(9,13),(11,12)],[(0,6),(1,5),(3,9),(4,10),(7,13),(8,12)],[(2,10),(3,11),(4,6),(7,9)],[(1,3),(2,8),(5,11),(6,7),(10,12)],[(1,4),(2,6),(3,5),(7,11),(8,10),(9,12)],[(2,4),(3,6),(5,8),(7,10),(9,11)],[(3,4),(5,6),(7,8),(9,10)],[(6,7)]]`},
+	{15, 56, 10, false,
+		`[[(1,2),(3,10),(4,14),(5,8),(6,13),(7,12),(9,11)],[(0,14),(1,5),(2,8),(3,7),(6,9),(10,12),(11,13)],[(0,7),(1,6),(2,9),(4,10),(5,11),(8,13),(12,14)],[(0,6),(2,4),(3,5),(7,11),(8,10),(9,12),(13,14)],[(0,3),(1,2),(4,7),(5,9),(6,8),(10,11),(12,13)],[(0,1),(2,3),(4,6),(7,9),(10,12),(11,13)],[(1,2),(3,5),(8,10),(11,12)],[(3,4),(5,6),(7,8),(9,10)],[(2,3),(4,5),(6,7),(8,9),(10,11)],[(5,6),(7,8)]]`},
+	{16, 60, 10, false,
+		`[[(0,13),(1,12),(2,15),(3,14),(4,8),(5,6),(7,11),(9,10)],[(0,5),(1,7),(2,9),(3,4),(6,13),(8,14),(10,15),(11,12)],[(0,1),(2,3),(4,5),(6,8),(7,9),(10,11),(12,13),(14,15)],[(0,2),(1,3),(4,10),(5,11),(6,7),(8,9),(12,14),(13,15)],[(1,2),(3,12),(4,6),(5,7),(8,10),(9,11),(13,14)],[(1,4),(2,6),(5,8),(7,10),(9,13),(11,14)],[(2,4),(3,6),(9,12),(11,13)],[(3,5),(6,8),(7,9),(10,12)],[(3,4),(5,6),(7,8),(9,10),(11,12)],[(6,7),(8,9)]]`},
+}
+
+var byDepth = []entry{
+	{2, 1, 1, true,
+		`[[(0,1)]]`},
+	{3, 3, 3, true,
+		`[[(0,2)],[(0,1)],[(1,2)]]`},
+	{4, 5, 3, true,
+		`[[(0,2),(1,3)],[(0,1),(2,3)],[(1,2)]]`},
+	{5, 9, 5, true,
+		`[[(0,3),(1,4)],[(0,2),(1,3)],[(0,1),(2,4)],[(1,2),(3,4)],[(2,3)]]`},
+	{6, 12, 5, true,
+		`[[(0,5),(1,3),(2,4)],[(1,2),(3,4)],[(0,3),(2,5)],[(0,1),(2,3),(4,5)],[(1,2),(3,4)]]`},
+	{7, 16, 6, true,
+		`[[(0,6),(2,3),(4,5)],[(0,2),(1,4),(3,6)],[(0,1),(2,5),(3,4)],[(1,2),(4,6)],[(2,3),(4,5)],[(1,2),(3,4),(5,6)]]`},
+	{8, 19, 6, true,
+		`[[(0,2),(1,3),(4,6),(5,7)],[(0,4),(1,5),(2,6),(3,7)],[(0,1),(2,3),(4,5),(6,7)],[(2,4),(3,5)],[(1,4),(3,6)],[(1,2),(3,4),(5,6)]]`},
+	{9, 25, 7, true,
+		`[[(0,3),(1,7),(2,5),(4,8)],[(0,7),(2,4),(3,8),(5,6)],[(0,2),(1,3),(4,5),(7,8)],[(1,4),(3,6),(5,7)],[(0,1),(2,4),(3,5),(6,8)],[(2,3),(4,5),(6,7)],[(1,2),(3,4),(5,6)]]`},
+	{10, 31, 7, true,
+		`[[(0,1),(2,5),(3,6),(4,7),(8,9)],[(0,6),(1,8),(2,4),(3,9),(5,7)],[(0,2),(1,3),(4,5),(6,8),(7,9)],[(0,1),(2,7),(3,5),(4,6),(8,9)],[(1,2),(3,4),(5,6),(7,8)],[(1,3),(2,4),(5,7),(6,8)],[(2,3),(4,5),(6,7)]]`},
+	{11, 35, 8, true,
+		`[[(0,9),(1,6),(2,4),(3,7),(5,8)],[(0,1),(3,5),(4,10),(6,9),(7,8)],[(1,3),(2,5),(4,7),(8,10)],[(0,4),(1,2),(3,7),(5,9),(6,8)],[(0,1),(2,6),(4,5),(7,8),(9,10)],[(2,4),(3,6),(5,7),(8,9)],[(1,2),(3,4),(5,6),(7,8)],[(2,3),(4,5),(6,7)]]`},
+	{12, 43, 8, true,
+		`[[(0,11),(1,10),(2,9),(3,8),(4,7),(5,6)],[(0,5),(1,4),(2,3),(6,11),(7,10),(8,9)],[(0,2),(3,5),(4,7),(6,8),(9,11)],[(0,4),(1,5),(2,3),(6,10),(7,11),(8,9)],[(1,2),(3,7),(4,8),(5,6),(9,10)],[(0,1),(2,3),(4,5),(6,7),(8,9),(10,11)],[(2,4),(3,5),(6,8),(7,9)],[(1,2),(3,4),(5,6),(7,8),(9,10)]]`},
+	{13, 47, 9, true,
+		`[[(0,1),(2,3),(4,5),(6,7),(8,9),(10,11)],[(0,2),(1,3),(4,6),(5,7),(8,10),(9,11)],[(0,4),(1,5),(2,6),(3,7),(8,12)],[(0,8),(1,9),(2,10),(3,11),(4,12)],[(1,2),(3,10),(4,8),(5,9),(6,12)],[(1,4),(2,8),(3,9),(5,12),(6,10),(7,11)],[(2,4),(3,5),(6,8),(7,10),(9,12)],[(3,6),(5,8),(7,9),(10,12)],[(3,4),(5,6),(7,8),(9,10),(11,12)]]`},
+	{14, 52, 9, true,
+		`[[(0,1),(2,3),(4,5),(6,7),(8,9),(10,11),(12,13)],[(0,2),(1,3),(4,6),(5,7),(8,10),(9,11)],[(0,4),(1,5),(2,6),(3,7),(8,12),(9,13)],[(0,8),(1,9),(2,10),(3,11),(4,12),(5,13)],[(1,2),(3,10),(4,8),(5,9),(6,12),(11,13)],[(1,4),(2,8),(3,9),(5,12),(6,10),(7,11)],[(2,4),(3,5),(6,8),(7,10),(9,12),(11,13)],[(3,6),(5,8),(7,9),(10,12)],[(3,4),(5,6),(7,8),(9,10),(11,12)]]`},
+	{15, 58, 9, true,
+		`[[(0,1),(2,3),(4,5),(6,7),(8,9),(10,11),(12,13)],[(0,2),(1,3),(4,6),(5,7),(8,10),(9,11),(12,14)],[(0,4),(1,5),(2,6),(3,7),(8,12),(9,13),(10,14)],[(0,8),(1,9),(2,10),(3,11),(4,12),(5,13),(6,14)],[(1,2),(3,10),(4,8),(5,9),(6,12),(7,14),(11,13)],[(1,4),(2,8),(3,9),(5,12),(6,10),(7,11)],[(2,4),(3,5),(6,8),(7,10),(9,12),(11,14)],[(3,6),(5,8),(7,9),(10,12),(11,13)],[(3,4),(5,6),(7,8),(9,10),(11,12),(13,14)]]`},
+	{16, 62, 9, true,
+		`[[(0,1),(2,3),(4,5),(6,7),(8,9),(10,11),(12,13),(14,15)],[(0,2),(1,3),(4,6),(5,7),(8,10),(9,11),(12,14),(13,15)],[(0,4),(1,5),(2,6),(3,7),(8,12),(9,13),(10,14),(11,15)],[(0,8),(1,9),(2,10),(3,11),(4,12),(5,13),(6,14),(7,15)],[(1,2),(3,10),(4,8),(5,9),(6,12),(7,14),(11,13)],[(1,4),(2,8),(3,9),(5,12),(6,10),(7,11)],[(2,4),(3,5),(6,8),(7,10),(9,12),(11,14)],[(3,6),(5,8),(7,9),(10,12),(11,13)],[(3,4),(5,6),(7,8),(9,10),(11,12),(13,14)]]`},
+}
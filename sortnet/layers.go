@@ -0,0 +1,158 @@
+package sortnet
+
+import "fmt"
+
+// NewLayeredComparatorNetwork creates a network from explicit layers, applied in order. Every layer must be a
+// matching, meaning no channel is used by more than one comparator of the layer, so its comparators can be applied in
+// parallel. The network keeps the layers as given, rather than scheduling its comparators as soon as possible.
+func NewLayeredComparatorNetwork(channels int, layers ...[]Comparator) (*ComparatorNetwork, error) {
+	var comparators []Comparator
+	sizes := make([]int, 0, len(layers))
+	for i, layer := range layers {
+		var used BinarySequence
+		for _, comparator := range layer {
+			if err := comparator.validate(channels); err != nil {
+				return nil, fmt.Errorf("layer %d: %w", i, err)
+			}
+			if used&comparator.mask() != 0 {
+				return nil, fmt.Errorf("layer %d: channel used twice by comparator from %d to %d", i, comparator.From, comparator.To)
+			}
+			used |= comparator.mask()
+		}
+
+		comparators = append(comparators, layer...)
+		sizes = append(sizes, len(layer))
+	}
+
+	network, err := NewComparatorNetwork(channels, comparators...)
+	if err != nil {
+		return nil, err
+	}
+
+	network.layers = sizes
+	return network, nil
+}
+
+// appendLayer returns a copy of the explicit layers followed by a layer of the given number of comparators. Without
+// explicit layers it returns nil.
+func appendLayer(layers []int, size int) []int {
+	if layers == nil {
+		return nil
+	}
+
+	extended := make([]int, len(layers), len(layers)+1)
+	copy(extended, layers)
+	return append(extended, size)
+}
+
+// layerOf assigns every comparator to the earliest layer after the layers of the comparators before it on the same
+// channels, known as as-soon-as-possible scheduling. A network created from explicit layers keeps them instead. It
+// returns the layer per comparator and the number of layers.
+func (n *ComparatorNetwork) layerOf() ([]int, int) {
+	layers := make([]int, len(n.comparators))
+	if n.layers != nil {
+		var i int
+		for layer, size := range n.layers {
+			for end := i + size; i < end; i++ {
+				layers[i] = layer
+			}
+		}
+		return layers, len(n.layers)
+	}
+
+	ready := make([]int, channelsOf(n.comparators))
+
+	var depth int
+	for i, comparator := range n.comparators {
+		layer := ready[comparator.From]
+		if ready[comparator.To] > layer {
+			layer = ready[comparator.To]
+		}
+
+		layers[i] = layer
+		ready[comparator.From], ready[comparator.To] = layer+1, layer+1
+		if layer+1 > depth {
+			depth = layer + 1
+		}
+	}
+
+	return layers, depth
+}
+
+// Depth returns the number of layers needed when comparators on distinct channels are applied in parallel, or the
+// number of explicit layers the network was created from.
+func (n *ComparatorNetwork) Depth() int {
+	_, depth := n.layerOf()
+	return depth
+}
+
+// Layers groups the comparators into Depth layers by as-soon-as-possible scheduling, or returns the explicit layers
+// the network was created from. Every layer is a matching, and comparators keep their relative order within a layer.
+func (n *ComparatorNetwork) Layers() [][]Comparator {
+	layerOf, depth := n.layerOf()
+
+	layers := make([][]Comparator, depth)
+	for i, comparator := range n.comparators {
+		layers[layerOf[i]] = append(layers[layerOf[i]], comparator)
+	}
+
+	return layers
+}
+
+// LayeredNetwork is a network that can be extended by a whole layer at a time, as used when searching by depth.
+type LayeredNetwork interface {
+	Network
+	DeriveLayers(layers [][]Comparator) []Network
+}
+
+// DeriveLayers creates a child network per layer, by appending the layer to a copy of this network.
+func (n *ComparatorNetwork) DeriveLayers(layers [][]Comparator) []Network {
+	children := make([]Network, 0, len(layers))
+	for _, layer := range layers {
+		child := &ComparatorNetwork{
+			channels:    n.channels,
+			comparators: make([]Comparator, len(n.comparators), len(n.comparators)+len(layer)),
+		}
+
+		copy(child.comparators, n.comparators)
+		child.comparators = append(child.comparators, layer...)
+		child.layers = appendLayer(n.layers, len(layer))
+
+		children = append(children, child)
+	}
+
+	return children
+}
+
+// Matchings returns every non-empty layer of comparators on the given channels. With maximal set, only the layers
+// where no further comparator can be added are returned, which leaves at most one channel unused.
+func Matchings(channels int, maximal bool) [][]Comparator {
+	var matchings [][]Comparator
+
+	var match func(channel int, used BinarySequence, layer []Comparator, unused int)
+	match = func(channel int, used BinarySequence, layer []Comparator, unused int) {
+		if maximal && unused > 1 {
+			return
+		}
+		if channel == channels {
+			if len(layer) > 0 {
+				matchings = append(matchings, append([]Comparator{}, layer...))
+			}
+			return
+		}
+		if used&(1<<channel) != 0 {
+			match(channel+1, used, layer, unused)
+			return
+		}
+
+		match(channel+1, used|1<<channel, layer, unused+1)
+		for partner := channel + 1; partner < channels; partner++ {
+			if used&(1<<partner) == 0 {
+				match(channel+1, used|1<<channel|1<<partner, append(layer, pair(channel, partner)), unused)
+			}
+		}
+	}
+	match(0, 0, nil, 0)
+
+	return matchings
+}
@@ -10,26 +10,84 @@ type Network interface {
 	Derive(comparators []Comparator) []Network
 }
 
+// NewComparatorNetwork creates a network of the given channels. Every comparator must move values from a higher channel
+// to a lower one, From > To, and both channels must be within the network.
+func NewComparatorNetwork(channels int, comparators ...Comparator) (*ComparatorNetwork, error) {
+	if channels < 0 || channels > MaxChannels {
+		return nil, fmt.Errorf("channels must be in the range [0, %d], got %d", MaxChannels, channels)
+	}
+	for i, comparator := range comparators {
+		if err := comparator.validate(channels); err != nil {
+			return nil, fmt.Errorf("comparator %d: %w", i, err)
+		}
+	}
+
+	network := &ComparatorNetwork{
+		channels:    channels,
+		comparators: make([]Comparator, len(comparators)),
+	}
+	copy(network.comparators, comparators)
+	return network, nil
+}
+
+// ComparatorNetwork is a sequence of comparators applied in order. The zero value is an empty network, whose channel
+// count is inferred from the comparators.
 type ComparatorNetwork struct {
+	channels    int
 	comparators []Comparator
+
+	// layers holds the number of comparators per layer of a network created from explicit layers, and is nil when
+	// the layers follow from as-soon-as-possible scheduling.
+	layers []int
 }
 
-func (n *ComparatorNetwork) String() string {
-	// simple ascii representation
-	// each dot represents a node in the channel
-	// each row represents a single channel
+// Comparators returns a copy of the comparators in the order they are applied.
+func (n *ComparatorNetwork) Comparators() []Comparator {
+	comparators := make([]Comparator, len(n.comparators))
+	copy(comparators, n.comparators)
+	return comparators
+}
+
+// Tail returns a copy of the comparators from the index on, such as the comparators a child added to its parent.
+func (n *ComparatorNetwork) Tail(from int) []Comparator {
+	comparators := make([]Comparator, len(n.comparators)-from)
+	copy(comparators, n.comparators[from:])
+	return comparators
+}
 
+// Len returns the number of comparators, also known as the size of the network.
+func (n *ComparatorNetwork) Len() int {
+	return len(n.comparators)
+}
+
+// Channels returns the number of channels of the network. When the network was not created with an explicit channel
+// count, the count is inferred from the highest channel used by a comparator.
+func (n *ComparatorNetwork) Channels() int {
+	if n.channels > 0 {
+		return n.channels
+	}
+
+	return channelsOf(n.comparators)
+}
+
+// channelsOf returns the number of channels needed to hold every comparator.
+func channelsOf(comparators []Comparator) int {
 	var channels int
-	for _, comparator := range n.comparators {
-		if comparator.From > channels {
-			channels = comparator.From
-		} else if comparator.To > channels {
-			channels = comparator.To
+	for _, comparator := range comparators {
+		if comparator.From+1 > channels {
+			channels = comparator.From + 1
 		}
 	}
+	return channels
+}
+
+func (n *ComparatorNetwork) String() string {
+	// simple ascii representation
+	// each dot represents a node in the channel
+	// each row represents a single channel
 
 	networkStr := strings.Builder{}
-	for channel := 0; channel <= channels; channel++ {
+	for channel := 0; channel < n.Channels(); channel++ {
 		channelStr := strings.Builder{}
 		channelStr.WriteString(fmt.Sprintf("%d: ", channel))
 		for _, comparator := range n.comparators {
@@ -73,11 +131,13 @@ func (n *ComparatorNetwork) Derive(comparators []Comparator) []Network {
 		}
 
 		child := &ComparatorNetwork{
-			comparators: make([]Comparator, len(n.comparators)),
+			channels:    n.channels,
+			comparators: make([]Comparator, len(n.comparators), len(n.comparators)+1),
 		}
 
 		copy(child.comparators, n.comparators)
 		child.comparators = append(child.comparators, comparator)
+		child.layers = appendLayer(n.layers, 1)
 
 		children = append(children, child)
 	}
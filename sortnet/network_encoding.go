@@ -0,0 +1,381 @@
+package sortnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The text encoding follows the notation common in literature, where each comparator is written as a pair (i,j) with
+// i < j. Channel i receives the minimum, which in the binary sequences of this package is the channel the set bit is
+// moved to. The comparator {From: 1, To: 0} is therefore written as (0,1).
+//
+//	flat:    [(0,1),(2,3),(0,2),(1,3),(1,2)]
+//	layered: [[(0,1),(2,3)],[(0,2),(1,3)],[(1,2)]]
+//
+// The channel count is written in front of the network, as in 5:[(0,1),(2,3)], only when the highest channel is not
+// used by any comparator and so can not be inferred.
+
+// FormatComparators writes the comparators in the flat text notation.
+func FormatComparators(comparators []Comparator) string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	writePairs(&sb, comparators)
+	sb.WriteString("]")
+	return sb.String()
+}
+
+func writeLayer(sb *strings.Builder, comparators []Comparator) {
+	sb.WriteString("[")
+	writePairs(sb, comparators)
+	sb.WriteString("]")
+}
+
+func writePairs(sb *strings.Builder, comparators []Comparator) {
+	for i, comparator := range comparators {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("(")
+		sb.WriteString(strconv.Itoa(comparator.To))
+		sb.WriteString(",")
+		sb.WriteString(strconv.Itoa(comparator.From))
+		sb.WriteString(")")
+	}
+}
+
+// ParseComparators reads comparators written in either the flat or the layered text notation. Every layer must be a
+// matching, meaning no channel is used twice within the same layer.
+func ParseComparators(text string) ([]Comparator, error) {
+	_, comparators, _, err := parse(text)
+	return comparators, err
+}
+
+// parse reads a network in either text notation. It returns the channel count when written, or 0, and the number of
+// comparators per layer when the network is layered, or nil.
+func parse(text string) (int, []Comparator, []int, error) {
+	p := &comparatorParser{text: text}
+	channels, err := p.channels()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	comparators, layers, err := p.network()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	p.skipSpace()
+	if p.pos != len(p.text) {
+		return 0, nil, nil, p.errorf("unexpected trailing text")
+	}
+
+	return channels, comparators, layers, nil
+}
+
+type comparatorParser struct {
+	text string
+	pos  int
+}
+
+func (p *comparatorParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *comparatorParser) skipSpace() {
+	for p.pos < len(p.text) && strings.ContainsRune(" \t\r\n", rune(p.text[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *comparatorParser) peek() byte {
+	p.skipSpace()
+	if p.pos == len(p.text) {
+		return 0
+	}
+	return p.text[p.pos]
+}
+
+func (p *comparatorParser) expect(c byte) error {
+	if p.peek() != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+// list parses a bracketed, comma separated list where each element is parsed by item.
+func (p *comparatorParser) list(item func() error) error {
+	if err := p.expect('['); err != nil {
+		return err
+	}
+	if p.peek() == ']' {
+		p.pos++
+		return nil
+	}
+
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return nil
+		}
+		if err := p.expect(','); err != nil {
+			return err
+		}
+	}
+}
+
+// channels reads the optional channel count in front of the network.
+func (p *comparatorParser) channels() (int, error) {
+	if p.peek() < '0' || p.peek() > '9' {
+		return 0, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.text) && p.text[p.pos] >= '0' && p.text[p.pos] <= '9' {
+		p.pos++
+	}
+	channels, err := strconv.Atoi(p.text[start:p.pos])
+	if err != nil || channels < 1 || channels > MaxChannels {
+		p.pos = start
+		return 0, p.errorf("channel count must be in the range [1, %d]", MaxChannels)
+	}
+
+	return channels, p.expect(':')
+}
+
+func (p *comparatorParser) network() ([]Comparator, []int, error) {
+	comparators := []Comparator{}
+	var layers []int
+	layered := strings.HasPrefix(p.afterBracket(), "[")
+
+	err := p.list(func() error {
+		if !layered {
+			comparator, err := p.pair()
+			if err != nil {
+				return err
+			}
+			comparators = append(comparators, comparator)
+			return nil
+		}
+
+		var used BinarySequence
+		layers = append(layers, 0)
+		return p.list(func() error {
+			comparator, err := p.pair()
+			if err != nil {
+				return err
+			}
+			if used&comparator.mask() != 0 {
+				return p.errorf("channel used twice in the same layer")
+			}
+			used |= comparator.mask()
+			comparators = append(comparators, comparator)
+			layers[len(layers)-1]++
+			return nil
+		})
+	})
+	if layered && layers == nil {
+		layers = []int{}
+	}
+
+	return comparators, layers, err
+}
+
+// afterBracket returns the text following the opening bracket of the network.
+func (p *comparatorParser) afterBracket() string {
+	if p.peek() != '[' {
+		return ""
+	}
+	return strings.TrimLeft(p.text[p.pos+1:], " \t\r\n")
+}
+
+func (p *comparatorParser) pair() (Comparator, error) {
+	if err := p.expect('('); err != nil {
+		return Comparator{}, err
+	}
+	low, err := p.channel()
+	if err != nil {
+		return Comparator{}, err
+	}
+	if err = p.expect(','); err != nil {
+		return Comparator{}, err
+	}
+	high, err := p.channel()
+	if err != nil {
+		return Comparator{}, err
+	}
+	if err = p.expect(')'); err != nil {
+		return Comparator{}, err
+	}
+
+	if low >= high {
+		return Comparator{}, p.errorf("comparator (%d,%d) must list the lowest channel first", low, high)
+	}
+
+	return Comparator{From: high, To: low}, nil
+}
+
+func (p *comparatorParser) channel() (int, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.text) && p.text[p.pos] >= '0' && p.text[p.pos] <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, p.errorf("expected a channel")
+	}
+
+	channel, err := strconv.Atoi(p.text[start:p.pos])
+	if err != nil || channel >= MaxChannels {
+		return 0, p.errorf("channel must be below %d", MaxChannels)
+	}
+
+	return channel, nil
+}
+
+func ParseComparatorNetwork(text string) (*ComparatorNetwork, error) {
+	network := &ComparatorNetwork{}
+	if err := network.UnmarshalText([]byte(text)); err != nil {
+		return nil, err
+	}
+
+	return network, nil
+}
+
+// MarshalText encodes the network in the layered text notation when it has explicit layers, and in the flat text
+// notation otherwise, such that UnmarshalText restores it exactly.
+func (n *ComparatorNetwork) MarshalText() ([]byte, error) {
+	if n.layers != nil {
+		return n.MarshalLayeredText()
+	}
+	return n.MarshalFlatText()
+}
+
+// MarshalFlatText encodes the network in the flat text notation.
+func (n *ComparatorNetwork) MarshalFlatText() ([]byte, error) {
+	return []byte(n.channelPrefix() + FormatComparators(n.comparators)), nil
+}
+
+// MarshalLayeredText encodes the network in the layered text notation, with one layer per step of Layers.
+func (n *ComparatorNetwork) MarshalLayeredText() ([]byte, error) {
+	var sb strings.Builder
+	sb.WriteString(n.channelPrefix())
+	sb.WriteString("[")
+	for i, layer := range n.Layers() {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		writeLayer(&sb, layer)
+	}
+	sb.WriteString("]")
+	return []byte(sb.String()), nil
+}
+
+// channelPrefix returns the channel count to write in front of the network, when it can not be inferred.
+func (n *ComparatorNetwork) channelPrefix() string {
+	if n.channels <= channelsOf(n.comparators) {
+		return ""
+	}
+	return strconv.Itoa(n.channels) + ":"
+}
+
+// UnmarshalText decodes a network written in either the flat or the layered text notation. Without a channel count in
+// front of the network, it is inferred from the highest channel used by a comparator. A layered network keeps its
+// layers, so it is written again as it was read by MarshalLayeredText.
+func (n *ComparatorNetwork) UnmarshalText(text []byte) error {
+	channels, comparators, layers, err := parse(string(text))
+	if err != nil {
+		return err
+	}
+	if channels == 0 {
+		channels = channelsOf(comparators)
+	}
+
+	network, err := NewComparatorNetwork(channels, comparators...)
+	if err != nil {
+		return err
+	}
+
+	network.layers = layers
+	*n = *network
+	return nil
+}
+
+type jsonNetwork struct {
+	Channels    int        `json:"channels"`
+	Comparators [][2]int   `json:"comparators"`
+	Layers      [][][2]int `json:"layers,omitempty"`
+}
+
+// MarshalJSON encodes the network as {"channels":4,"comparators":[[0,1],[2,3],...]}, using the same pair order as the
+// text notation. A network with explicit layers also lists the comparators per layer in "layers".
+func (n *ComparatorNetwork) MarshalJSON() ([]byte, error) {
+	encoded := jsonNetwork{
+		Channels:    n.Channels(),
+		Comparators: jsonPairs(n.comparators),
+	}
+	if n.layers != nil {
+		for _, layer := range n.Layers() {
+			encoded.Layers = append(encoded.Layers, jsonPairs(layer))
+		}
+	}
+
+	return json.Marshal(encoded)
+}
+
+func jsonPairs(comparators []Comparator) [][2]int {
+	pairs := make([][2]int, 0, len(comparators))
+	for _, comparator := range comparators {
+		pairs = append(pairs, [2]int{comparator.To, comparator.From})
+	}
+	return pairs
+}
+
+func jsonComparators(pairs [][2]int) []Comparator {
+	comparators := make([]Comparator, 0, len(pairs))
+	for _, pair := range pairs {
+		comparators = append(comparators, Comparator{From: pair[1], To: pair[0]})
+	}
+	return comparators
+}
+
+func (n *ComparatorNetwork) UnmarshalJSON(data []byte) error {
+	var decoded jsonNetwork
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	comparators := jsonComparators(decoded.Comparators)
+	if decoded.Channels == 0 {
+		decoded.Channels = channelsOf(comparators)
+	}
+	if decoded.Layers == nil {
+		network, err := NewComparatorNetwork(decoded.Channels, comparators...)
+		if err != nil {
+			return err
+		}
+
+		*n = *network
+		return nil
+	}
+
+	layers := make([][]Comparator, len(decoded.Layers))
+	for i, layer := range decoded.Layers {
+		layers[i] = jsonComparators(layer)
+	}
+	network, err := NewLayeredComparatorNetwork(decoded.Channels, layers...)
+	if err != nil {
+		return err
+	}
+	if FormatComparators(network.comparators) != FormatComparators(comparators) {
+		return errors.New("the layers do not hold the comparators of the network")
+	}
+
+	*n = *network
+	return nil
+}
@@ -0,0 +1,123 @@
+package sortnet
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestComparatorNetworkText(t *testing.T) {
+	const flat = "[(0,1),(2,3),(0,2),(1,3),(1,2)]"
+	const layered = "[[(0,1),(2,3)],[(0,2),(1,3)],[(1,2)]]"
+
+	for _, text := range []string{flat, layered, " [ [ (0, 1), (2,3) ] , [(0,2),(1,3)],[(1,2)] ]\n"} {
+		network, err := ParseComparatorNetwork(text)
+		if err != nil {
+			t.Fatalf("unable to parse %q: %s", text, err)
+		}
+
+		if data, _ := network.MarshalFlatText(); string(data) != flat {
+			t.Errorf("expected %s, got %s", flat, data)
+		}
+		if data, _ := network.MarshalLayeredText(); string(data) != layered {
+			t.Errorf("expected %s, got %s", layered, data)
+		}
+	}
+
+	for _, text := range []string{"[]", "[[]]"} {
+		network, err := ParseComparatorNetwork(text)
+		if err != nil {
+			t.Fatalf("unable to parse %q: %s", text, err)
+		}
+		if data, _ := network.MarshalFlatText(); string(data) != "[]" {
+			t.Errorf("expected an empty network, got %s", data)
+		}
+	}
+
+	for _, text := range []string{"", "(0,1)", "[(1,0)]", "[(0,1)", "[(0,1),]", "[(0,1,2)]", "[[(0,1),(1,2)]]", "[(0,1)] x", "[(0,99)]"} {
+		if _, err := ParseComparatorNetwork(text); err == nil {
+			t.Errorf("expected %q to be rejected", text)
+		}
+	}
+}
+
+func TestComparatorNetworkJSON(t *testing.T) {
+	network, err := ParseComparatorNetwork("[(0,1),(2,3),(0,2),(1,3),(1,2)]")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(network)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"channels":4,"comparators":[[0,1],[2,3],[0,2],[1,3],[1,2]]}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	decoded := &ComparatorNetwork{}
+	if err = json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.String() != network.String() {
+		t.Errorf("expected the decoded network to equal the original")
+	}
+
+	for _, invalid := range []string{`{"comparators":[[1,0]]}`, `{"channels":2,"comparators":[[0,2]]}`} {
+		if err = json.Unmarshal([]byte(invalid), decoded); err == nil {
+			t.Errorf("expected %s to be rejected", invalid)
+		}
+	}
+}
+
+func TestComparatorNetworkRoundTrip(t *testing.T) {
+	texts := []string{
+		"[]", "[(0,1),(2,3),(0,2),(1,3),(1,2)]", "[(1,2),(0,1)]", "6:[(0,1),(2,3)]", "3:[]",
+		"[[]]", "[[(0,1),(3,4)],[(1,2)]]", "[[(0,1)],[(2,3)]]", "[[(2,3),(0,1)],[],[(1,2)]]", "7:[[(0,1)],[(2,3)]]",
+	}
+	for _, text := range texts {
+		network, err := ParseComparatorNetwork(text)
+		if err != nil {
+			t.Fatalf("unable to parse %q: %s", text, err)
+		}
+		if data, _ := network.MarshalText(); string(data) != text {
+			t.Errorf("expected %s to round-trip as text, got %s", text, data)
+		}
+
+		data, err := json.Marshal(network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded := &ComparatorNetwork{}
+		if err = json.Unmarshal(data, decoded); err != nil {
+			t.Fatalf("unable to decode %s: %s", data, err)
+		}
+		if !reflect.DeepEqual(decoded, network) {
+			t.Errorf("expected %s to round-trip as JSON, got %s", text, data)
+		}
+	}
+
+	network, err := NewLayeredComparatorNetwork(5, []Comparator{{From: 1, To: 0}}, []Comparator{{From: 3, To: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := network.MarshalText(); string(data) != "5:[[(0,1)],[(2,3)]]" {
+		t.Errorf("expected the explicit layers and channels, got %s", data)
+	}
+	if data, _ := json.Marshal(network); string(data) != `{"channels":5,"comparators":[[0,1],[2,3]],"layers":[[[0,1]],[[2,3]]]}` {
+		t.Errorf("expected the layers in the JSON encoding, got %s", data)
+	}
+	invalid := `{"channels":5,"comparators":[[0,1]],"layers":[[[2,3]]]}`
+	if err = json.Unmarshal([]byte(invalid), &ComparatorNetwork{}); err == nil {
+		t.Errorf("expected %s to be rejected", invalid)
+	}
+	if network.Depth() != 2 || len(network.Layers()) != 2 {
+		t.Errorf("expected a depth of 2, got %d", network.Depth())
+	}
+
+	for _, text := range []string{"3:[(0,3)]", "0:[]", "5[(0,1)]", "99:[]"} {
+		if _, err := ParseComparatorNetwork(text); err == nil {
+			t.Errorf("expected %q to be rejected", text)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package sortnet
+
+import "sync"
+
+// RootNetwork is the ID of the empty network every store starts with.
+const RootNetwork NetworkID = 0
+
+// storedNetwork is a network as its parent and the comparator appended to it. refs counts the children and the
+// holders of the network, and the network is removed once it drops to zero.
+type storedNetwork struct {
+	parent     NetworkID
+	comparator Comparator
+	refs       int32
+}
+
+// NetworkStore keeps networks as a prefix tree, where every network only stores its last comparator and a link to
+// its parent. A network costs the same memory regardless of its size, and networks sharing a prefix share its nodes.
+// The full comparator list is rebuilt on demand by following the parent links. It is safe for concurrent use.
+type NetworkStore struct {
+	mu       sync.RWMutex
+	channels int
+	networks []storedNetwork
+	free     []NetworkID
+}
+
+func NewNetworkStore(channels int) *NetworkStore {
+	return &NetworkStore{
+		channels: channels,
+		networks: []storedNetwork{{parent: RootNetwork, refs: 1}},
+	}
+}
+
+// Add extends the parent network by the comparators, one stored network per comparator, and holds the last one until
+// it is released. Without comparators the parent itself is held again.
+func (s *NetworkStore) Add(parent NetworkID, comparators ...Comparator) NetworkID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := parent
+	for _, comparator := range comparators {
+		s.networks[id].refs++
+		child := s.allocate()
+		s.networks[child] = storedNetwork{parent: id, comparator: comparator}
+		id = child
+	}
+	s.networks[id].refs++
+
+	return id
+}
+
+func (s *NetworkStore) allocate() NetworkID {
+	if n := len(s.free); n > 0 {
+		id := s.free[n-1]
+		s.free = s.free[:n-1]
+		return id
+	}
+
+	s.networks = append(s.networks, storedNetwork{})
+	return NetworkID(len(s.networks) - 1)
+}
+
+// Release drops a hold on the network. Once a network is neither held nor has children it is removed, and so are the
+// ancestors that only led to it. The ID of a removed network is issued again by a later Add.
+func (s *NetworkStore) Release(id NetworkID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id != RootNetwork {
+		stored := &s.networks[id]
+		if stored.refs--; stored.refs > 0 {
+			return
+		}
+
+		parent := stored.parent
+		*stored = storedNetwork{}
+		s.free = append(s.free, id)
+		id = parent
+	}
+}
+
+// Len returns the number of networks in the store, including the root and every prefix of a held network.
+func (s *NetworkStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.networks) - len(s.free)
+}
+
+// Last returns the comparator the network appended to its parent. The root network has none.
+func (s *NetworkStore) Last(id NetworkID) (Comparator, bool) {
+	if id == RootNetwork {
+		return Comparator{}, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.networks[id].comparator, true
+}
+
+// Lineage returns the IDs of the network and its ancestors, starting with the root network. The network at index i
+// holds i comparators.
+func (s *NetworkStore) Lineage(id NetworkID) []NetworkID {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lineage := []NetworkID{id}
+	for id != RootNetwork {
+		id = s.networks[id].parent
+		lineage = append(lineage, id)
+	}
+	for i, j := 0, len(lineage)-1; i < j; i, j = i+1, j-1 {
+		lineage[i], lineage[j] = lineage[j], lineage[i]
+	}
+
+	return lineage
+}
+
+// Comparators rebuilds the comparator list of the network, in the order they are applied.
+func (s *NetworkStore) Comparators(id NetworkID) []Comparator {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var comparators []Comparator
+	for ; id != RootNetwork; id = s.networks[id].parent {
+		comparators = append(comparators, s.networks[id].comparator)
+	}
+	for i, j := 0, len(comparators)-1; i < j; i, j = i+1, j-1 {
+		comparators[i], comparators[j] = comparators[j], comparators[i]
+	}
+
+	return comparators
+}
+
+// Network rebuilds the network as a ComparatorNetwork.
+func (s *NetworkStore) Network(id NetworkID) *ComparatorNetwork {
+	return &ComparatorNetwork{
+		channels:    s.channels,
+		comparators: s.Comparators(id),
+	}
+}
@@ -0,0 +1,56 @@
+package sortnet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNetworkStore(t *testing.T) {
+	store := NewNetworkStore(4)
+
+	parent := store.Add(RootNetwork, pair(0, 1))
+	a := store.Add(parent, pair(2, 3), pair(0, 2))
+	b := store.Add(parent, pair(1, 3))
+	if store.Len() != 5 {
+		t.Fatalf("expected 5 stored networks, got %d", store.Len())
+	}
+
+	expected := []Comparator{pair(0, 1), pair(2, 3), pair(0, 2)}
+	if comparators := store.Comparators(a); !reflect.DeepEqual(comparators, expected) {
+		t.Errorf("expected comparators %v, got %v", expected, comparators)
+	}
+	if network := store.Network(a); network.Channels() != 4 || network.Len() != 3 {
+		t.Errorf("expected a network of 4 channels and 3 comparators, got %s", network)
+	}
+	if last, ok := store.Last(b); !ok || last != pair(1, 3) {
+		t.Errorf("expected the last comparator to be %v, got %v", pair(1, 3), last)
+	}
+	if _, ok := store.Last(RootNetwork); ok {
+		t.Error("expected the root network to have no comparator")
+	}
+
+	lineage := store.Lineage(a)
+	if len(lineage) != 4 || lineage[0] != RootNetwork || lineage[1] != parent || lineage[3] != a {
+		t.Errorf("unexpected lineage %v", lineage)
+	}
+
+	// the parent is still held, so releasing a only removes a and its prefix of (2,3)
+	store.Release(a)
+	if store.Len() != 3 {
+		t.Errorf("expected 3 stored networks after releasing a, got %d", store.Len())
+	}
+
+	store.Release(parent)
+	if store.Len() != 3 {
+		t.Errorf("expected b to keep its parent, got %d stored networks", store.Len())
+	}
+
+	store.Release(b)
+	if store.Len() != 1 {
+		t.Errorf("expected only the root network, got %d stored networks", store.Len())
+	}
+
+	if id := store.Add(RootNetwork, pair(0, 3)); id == RootNetwork || int(id) > 4 {
+		t.Errorf("expected a released ID to be issued again, got %d", id)
+	}
+}
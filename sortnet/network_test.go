@@ -0,0 +1,108 @@
+package sortnet
+
+import "testing"
+
+func TestNewComparatorNetwork(t *testing.T) {
+	comparators := []Comparator{{From: 1, To: 0}, {From: 2, To: 1}}
+	network, err := NewComparatorNetwork(4, comparators...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if network.Channels() != 4 {
+		t.Errorf("expected 4 channels, got %d", network.Channels())
+	}
+	if network.Len() != 2 {
+		t.Errorf("expected 2 comparators, got %d", network.Len())
+	}
+
+	network.Comparators()[0] = Comparator{From: 3, To: 2}
+	comparators[1] = Comparator{From: 3, To: 2}
+	if network.Comparators()[0] != (Comparator{From: 1, To: 0}) || network.Comparators()[1] != (Comparator{From: 2, To: 1}) {
+		t.Error("expected the network to keep its own copy of the comparators")
+	}
+
+	for _, child := range network.Derive(AllComparatorCombinations(4)) {
+		if child.(*ComparatorNetwork).Channels() != 4 {
+			t.Error("expected derived networks to keep the channel count")
+		}
+	}
+
+	invalid := [][]Comparator{
+		{{From: 0, To: 1}},
+		{{From: 1, To: 1}},
+		{{From: 4, To: 0}},
+		{{From: 1, To: -1}},
+	}
+	for _, comparators := range invalid {
+		if _, err = NewComparatorNetwork(4, comparators...); err == nil {
+			t.Errorf("expected %v to be rejected", comparators)
+		}
+	}
+	if _, err = NewComparatorNetwork(MaxChannels + 1); err == nil {
+		t.Error("expected too many channels to be rejected")
+	}
+}
+
+func TestLayers(t *testing.T) {
+	// the last comparator does not depend on the others and is moved to the first layer
+	network, err := NewComparatorNetwork(5, pair(0, 1), pair(0, 2), pair(1, 2), pair(3, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if network.Depth() != 3 {
+		t.Errorf("expected depth 3, got %d", network.Depth())
+	}
+
+	expected := "[[(0,1),(3,4)],[(0,2)],[(1,2)]]"
+	if data, _ := network.MarshalLayeredText(); string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	layered, err := NewLayeredComparatorNetwork(5, network.Layers()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layered.Depth() != 3 || layered.Len() != 4 {
+		t.Errorf("expected depth 3 and 4 comparators, got %d and %d", layered.Depth(), layered.Len())
+	}
+
+	if _, err = NewLayeredComparatorNetwork(4, []Comparator{pair(0, 1), pair(1, 2)}); err == nil {
+		t.Error("expected a layer using a channel twice to be rejected")
+	}
+	if _, err = NewLayeredComparatorNetwork(4, []Comparator{pair(0, 4)}); err == nil {
+		t.Error("expected a comparator outside of the channels to be rejected")
+	}
+
+	// the layered text follows Layers, rather than grouping consecutive comparators
+	network, _ = ParseComparatorNetwork("[(0,1),(1,2),(3,4)]")
+	if data, _ := network.MarshalLayeredText(); string(data) != "[[(0,1),(3,4)],[(1,2)]]" || network.Depth() != 2 {
+		t.Errorf("expected 2 layers [[(0,1),(3,4)],[(1,2)]], got %d layers %s", network.Depth(), data)
+	}
+
+	if (&ComparatorNetwork{}).Depth() != 0 {
+		t.Error("expected the empty network to have depth 0")
+	}
+}
+
+func TestMatchings(t *testing.T) {
+	// number of matchings and perfect or near-perfect matchings of the complete graph
+	all := []int{0, 0, 1, 3, 9, 25, 75, 231}
+	maximal := []int{0, 0, 1, 3, 3, 15, 15, 105}
+
+	for channels := 1; channels < len(all); channels++ {
+		if n := len(Matchings(channels, false)); n != all[channels] {
+			t.Errorf("expected %d matchings of %d channels, got %d", all[channels], channels, n)
+		}
+		if n := len(Matchings(channels, true)); n != maximal[channels] {
+			t.Errorf("expected %d maximal matchings of %d channels, got %d", maximal[channels], channels, n)
+		}
+	}
+
+	for _, layer := range Matchings(6, false) {
+		if _, err := NewLayeredComparatorNetwork(6, layer); err != nil {
+			t.Errorf("invalid layer %s: %s", FormatComparators(layer), err)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package sortnet
 
+import "encoding"
+
 type OutputSet interface {
 	Derive(Network) OutputSet
 	Add(sequence BinarySequence)
@@ -8,6 +10,23 @@ type OutputSet interface {
 	Contains(BinarySequence) bool
 	ContainsInPartition(seq BinarySequence, partition int) bool
 	Metadata() *SetMetadata
+
+	// Elements returns every sequence of the set. Adding them in the same order to NewEmpty recreates the set.
+	Elements() []BinarySequence
+
+	// NewEmpty creates an empty set of the same implementation and channels.
+	NewEmpty() OutputSet
+
+	// MarshalBinary and UnmarshalBinary use a format shared by every implementation, such that a set can be decoded by
+	// another implementation than the one it was encoded by. Decoding rebuilds the metadata.
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// DeriveFrom creates the output set of a network extended by the comparators, from the output set of the network. Only
+// the sequences of the parent set are transformed, rather than every input of the channels.
+func DeriveFrom(parent OutputSet, comparators ...Comparator) OutputSet {
+	return parent.Derive(&ComparatorNetwork{comparators: comparators})
 }
 
 func PopulateOutputSet(set OutputSet, channels int) OutputSet {
@@ -0,0 +1,118 @@
+package outputset
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+// MaxDenseChannels is the most channels a Dense set supports, where a set takes 8KiB.
+const MaxDenseChannels = 16
+
+// NewEmptyDense panics when channels exceeds MaxDenseChannels.
+func NewEmptyDense(channels int) *Dense {
+	if channels > MaxDenseChannels {
+		panic(fmt.Sprintf("dense sets support at most %d channels, got %d", MaxDenseChannels, channels))
+	}
+
+	words := (1<<channels + 63) / 64
+	return &Dense{
+		Bits:        make([]uint64, words),
+		SetMetadata: &sortnet.SetMetadata{},
+		channels:    channels,
+	}
+}
+
+func NewDense(channels int) sortnet.OutputSet {
+	return sortnet.PopulateOutputSet(NewEmptyDense(channels), channels)
+}
+
+// Dense holds a bit per possible binary sequence, indexed by the sequence value. Contains is a single bit test and a
+// subset test without a permutation is a word-wise comparison, at the cost of 2^channels bits per set no matter how
+// few sequences it holds, so at most MaxDenseChannels are supported.
+type Dense struct {
+	Bits []uint64
+	*sortnet.SetMetadata
+
+	channels int
+}
+
+func (s *Dense) Metadata() *sortnet.SetMetadata {
+	return s.SetMetadata
+}
+
+// Contains reports false for sequences beyond the channels of the set.
+func (s *Dense) Contains(seq sortnet.BinarySequence) bool {
+	return int(seq/64) < len(s.Bits) && s.Bits[seq/64]&(1<<(seq%64)) != 0
+}
+
+func (s *Dense) ContainsInPartition(seq sortnet.BinarySequence, _ int) bool {
+	return s.Contains(seq)
+}
+
+func (s *Dense) Add(seq sortnet.BinarySequence) {
+	if !s.Contains(seq) {
+		s.Bits[seq/64] |= 1 << (seq % 64)
+		s.SetMetadata.Add(seq, seq.OnesCount())
+	}
+}
+
+// each calls fn for every sequence in the set, in increasing order.
+func (s *Dense) each(fn func(seq sortnet.BinarySequence) bool) bool {
+	for i, word := range s.Bits {
+		for word != 0 {
+			offset := bits.TrailingZeros64(word)
+			if !fn(sortnet.BinarySequence(i*64 + offset)) {
+				return false
+			}
+			word &= word - 1
+		}
+	}
+	return true
+}
+
+func (s *Dense) Derive(network sortnet.Network) sortnet.OutputSet {
+	output := NewEmptyDense(s.channels)
+	s.each(func(seq sortnet.BinarySequence) bool {
+		output.Add(network.Transform(seq))
+		return true
+	})
+	return output
+}
+
+func (s *Dense) Size() int {
+	return s.SetMetadata.Size
+}
+
+func (s *Dense) Elements() []sortnet.BinarySequence {
+	elements := make([]sortnet.BinarySequence, 0, s.Size())
+	s.each(func(seq sortnet.BinarySequence) bool {
+		elements = append(elements, seq)
+		return true
+	})
+	return elements
+}
+
+func (s *Dense) NewEmpty() sortnet.OutputSet {
+	return NewEmptyDense(s.channels)
+}
+
+func (s *Dense) IsSubset(other sortnet.OutputSet, permutation sortnet.PermutationMap) bool {
+	// the word-wise comparison needs the other set to hold every word of this one
+	if dense, ok := other.(*Dense); ok && permutation == nil && len(s.Bits) <= len(dense.Bits) {
+		for i, word := range s.Bits {
+			if word&^dense.Bits[i] != 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	return s.each(func(seq sortnet.BinarySequence) bool {
+		if permutation != nil {
+			seq = sortnet.ApplyPermutation(seq, permutation)
+		}
+		return other.Contains(seq)
+	})
+}
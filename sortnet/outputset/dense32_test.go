@@ -0,0 +1,31 @@
+//go:build sortnet32 || sortnet64
+
+package outputset
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+func TestDenseChannels(t *testing.T) {
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected a panic for more channels than a dense set supports")
+			}
+		}()
+		NewEmptyDense(MaxDenseChannels + 1)
+	}()
+
+	unordered := NewEmptyUnordered()
+	unordered.Add(sortnet.BinarySequence(1) << 19)
+	data, err := unordered.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Dense{}).UnmarshalBinary(data); !errors.Is(err, ErrEncoding) {
+		t.Errorf("expected an encoding error for 20 channels, got %v", err)
+	}
+}
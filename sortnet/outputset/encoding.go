@@ -0,0 +1,233 @@
+package outputset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+// encodingVersion is the first byte of every encoded output set.
+const encodingVersion = 1
+
+var ErrEncoding = errors.New("malformed output set encoding")
+
+// marshal encodes the sequences in the format shared by every implementation, so a set can be decoded by any other:
+//
+//	version   byte
+//	channels  uvarint
+//	partitions uvarint, followed per partition, by the number of ones, with
+//	  size      uvarint
+//	  sequences uvarint each, in increasing order as the difference to the previous one
+//
+// When channels is 0 it is taken from the highest channel used by a sequence.
+func marshal(channels int, sequences []sortnet.BinarySequence) ([]byte, error) {
+	var partitions [][]sortnet.BinarySequence
+	for _, seq := range sequences {
+		partition := seq.OnesCount()
+		for len(partitions) <= partition {
+			partitions = append(partitions, nil)
+		}
+		partitions[partition] = append(partitions[partition], seq)
+
+		if width := bits.Len64(uint64(seq)); width > channels {
+			channels = width
+		}
+	}
+
+	data := make([]byte, 0, 1+2*binary.MaxVarintLen64+len(sequences)*2)
+	data = append(data, encodingVersion)
+	data = binary.AppendUvarint(data, uint64(channels))
+	data = binary.AppendUvarint(data, uint64(len(partitions)))
+	for _, partition := range partitions {
+		sort.Slice(partition, func(i, j int) bool { return partition[i] < partition[j] })
+
+		data = binary.AppendUvarint(data, uint64(len(partition)))
+		var previous sortnet.BinarySequence
+		for _, seq := range partition {
+			data = binary.AppendUvarint(data, uint64(seq-previous))
+			previous = seq
+		}
+	}
+
+	return data, nil
+}
+
+// unmarshal decodes the channels and the sequences of an encoded set, ordered by partition.
+func unmarshal(data []byte) (int, []sortnet.BinarySequence, error) {
+	if len(data) == 0 || data[0] != encodingVersion {
+		return 0, nil, fmt.Errorf("%w: unknown version", ErrEncoding)
+	}
+	data = data[1:]
+
+	next := func() (uint64, error) {
+		value, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, fmt.Errorf("%w: truncated", ErrEncoding)
+		}
+		data = data[n:]
+		return value, nil
+	}
+
+	channels, err := next()
+	if err != nil {
+		return 0, nil, err
+	}
+	if channels > sortnet.MaxChannels {
+		return 0, nil, fmt.Errorf("%w: %d channels exceed the maximum of %d", ErrEncoding, channels, sortnet.MaxChannels)
+	}
+	partitions, err := next()
+	if err != nil {
+		return 0, nil, err
+	}
+	if partitions > channels+1 {
+		return 0, nil, fmt.Errorf("%w: %d partitions for %d channels", ErrEncoding, partitions, channels)
+	}
+
+	var sequences []sortnet.BinarySequence
+	for partition := 0; partition < int(partitions); partition++ {
+		size, err := next()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		var seq uint64
+		for i := uint64(0); i < size; i++ {
+			delta, err := next()
+			if err != nil {
+				return 0, nil, err
+			}
+			if seq += delta; bits.Len64(seq) > int(channels) || bits.OnesCount64(seq) != partition {
+				return 0, nil, fmt.Errorf("%w: sequence %b does not belong to partition %d", ErrEncoding, seq, partition)
+			}
+			sequences = append(sequences, sortnet.BinarySequence(seq))
+		}
+	}
+	if len(data) > 0 {
+		return 0, nil, fmt.Errorf("%w: %d trailing bytes", ErrEncoding, len(data))
+	}
+
+	return int(channels), sequences, nil
+}
+
+func (s *Unordered) MarshalBinary() ([]byte, error) {
+	return marshal(s.channels, s.Elements())
+}
+
+// UnmarshalBinary replaces the set with the decoded one, and rebuilds its metadata. The set keeps its channels when
+// they exceed the encoded ones.
+func (s *Unordered) UnmarshalBinary(data []byte) error {
+	channels, sequences, err := unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if s.channels > channels {
+		channels = s.channels
+	}
+
+	*s = *NewEmptyUnordered().(*Unordered)
+	s.channels = channels
+	for _, seq := range sequences {
+		s.Add(seq)
+	}
+	return nil
+}
+
+func (s *PartitionedOrdered) MarshalBinary() ([]byte, error) {
+	return marshal(s.channels, s.Elements())
+}
+
+// UnmarshalBinary replaces the set with the decoded one, and rebuilds its metadata. The set keeps its channels when
+// they exceed the encoded ones.
+func (s *PartitionedOrdered) UnmarshalBinary(data []byte) error {
+	channels, sequences, err := unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if s.channels > channels {
+		channels = s.channels
+	}
+
+	*s = *NewEmptyPartitionedOrdered()
+	s.channels = channels
+	for _, seq := range sequences {
+		s.Add(seq)
+	}
+	return nil
+}
+
+func (s *PartitionedUnordered) MarshalBinary() ([]byte, error) {
+	return marshal(s.channels, s.Elements())
+}
+
+// UnmarshalBinary replaces the set with the decoded one, and rebuilds its metadata. The set keeps its channels when
+// they exceed the encoded ones.
+func (s *PartitionedUnordered) UnmarshalBinary(data []byte) error {
+	channels, sequences, err := unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if s.channels > channels {
+		channels = s.channels
+	}
+
+	*s = *NewEmptyPartitionedUnordered().(*PartitionedUnordered)
+	s.channels = channels
+	for _, seq := range sequences {
+		s.Add(seq)
+	}
+	return nil
+}
+
+func (s *Warhol) MarshalBinary() ([]byte, error) {
+	return marshal(len(s.Channels), s.Elements())
+}
+
+// UnmarshalBinary replaces the set with the decoded one, and rebuilds its metadata. The set keeps its channels when
+// they exceed the encoded ones.
+func (s *Warhol) UnmarshalBinary(data []byte) error {
+	channels, sequences, err := unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if len(s.Channels) > channels {
+		channels = len(s.Channels)
+	}
+	if channels > MaxWarholChannels {
+		return fmt.Errorf("%w: warhol sets support at most %d channels, got %d", ErrEncoding, MaxWarholChannels, channels)
+	}
+
+	*s = *NewEmptyWarhol(channels)
+	for _, seq := range sequences {
+		s.Add(seq)
+	}
+	return nil
+}
+
+func (s *Dense) MarshalBinary() ([]byte, error) {
+	return marshal(s.channels, s.Elements())
+}
+
+// UnmarshalBinary replaces the set with the decoded one, and rebuilds its metadata. The set keeps its channels when
+// they exceed the encoded ones.
+func (s *Dense) UnmarshalBinary(data []byte) error {
+	channels, sequences, err := unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if s.channels > channels {
+		channels = s.channels
+	}
+	if channels > MaxDenseChannels {
+		return fmt.Errorf("%w: dense sets support at most %d channels, got %d", ErrEncoding, MaxDenseChannels, channels)
+	}
+
+	*s = *NewEmptyDense(channels)
+	for _, seq := range sequences {
+		s.Add(seq)
+	}
+	return nil
+}
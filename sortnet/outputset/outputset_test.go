@@ -0,0 +1,198 @@
+package outputset
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+func TestOutputSets(t *testing.T) {
+	const channels = 6
+	implementations := map[string]NewSet{
+		"partitioned-ordered":   NewPartitionedOrdered,
+		"partitioned-unordered": NewPartitionedUnordered,
+		"unordered":             NewUnordered,
+		"warhol":                NewWarhol,
+		"dense":                 NewDense,
+	}
+
+	network := sortnet.NewBatcherOddEvenMergeSort(channels)
+	prefix, _ := sortnet.NewComparatorNetwork(channels, network.Comparators()[:5]...)
+	reference := NewPartitionedOrdered(channels).Derive(prefix)
+
+	// reverses the channels, which maps the output set of the prefix onto another one
+	reverse := sortnet.PermutationMap{5, 4, 3, 2, 1, 0}
+
+	for name, newSet := range implementations {
+		set := newSet(channels)
+		if set.Size() != 1<<channels-2 {
+			t.Errorf("%s: expected %d sequences, got %d", name, 1<<channels-2, set.Size())
+		}
+
+		derived := set.Derive(prefix)
+		if derived.Size() != reference.Size() {
+			t.Errorf("%s: expected %d sequences after the prefix, got %d", name, reference.Size(), derived.Size())
+		}
+		for _, seq := range reference.Elements() {
+			if !derived.Contains(seq) {
+				t.Errorf("%s: expected %b in the output set", name, seq)
+			}
+		}
+		if !derived.IsSubset(set, nil) || set.IsSubset(derived, nil) {
+			t.Errorf("%s: expected the output set to be a strict subset of all sequences", name)
+		}
+
+		incremental := set
+		for _, comparator := range prefix.Comparators() {
+			incremental = sortnet.DeriveFrom(incremental, comparator)
+		}
+		if incremental.Size() != derived.Size() || !incremental.IsSubset(derived, nil) {
+			t.Errorf("%s: expected the comparators applied one at a time to give the output set of the prefix", name)
+		}
+
+		sliced := sortnet.BitSlicedOutputSet(set.NewEmpty(), prefix, channels)
+		if sliced.Size() != derived.Size() || !sliced.IsSubset(derived, nil) {
+			t.Errorf("%s: expected the bit sliced output set to equal the derived one", name)
+		}
+
+		restored := derived.NewEmpty()
+		for _, seq := range derived.Elements() {
+			restored.Add(seq)
+		}
+		if restored.Size() != derived.Size() || !restored.IsSubset(derived, nil) {
+			t.Errorf("%s: expected the elements to recreate the set", name)
+		}
+
+		md, expected := derived.Metadata(), reference.Metadata()
+		for p := range expected.PartitionSizes {
+			if md.PartitionSizes[p] != expected.PartitionSizes[p] || md.OnesMasks[p] != expected.OnesMasks[p] {
+				t.Errorf("%s: metadata of partition %d differs", name, p)
+			}
+		}
+
+		if sorted := set.Derive(network); sorted.Size() != channels-1 {
+			t.Errorf("%s: expected the sorting network to leave %d sequences, got %d", name, channels-1, sorted.Size())
+		}
+
+		mirrored := newSet(channels).NewEmpty()
+		for _, seq := range derived.Elements() {
+			mirrored.Add(sortnet.ApplyPermutation(seq, reverse))
+		}
+		// Warhol can not test permuted subsets, see its documentation
+		if name != "warhol" && !derived.IsSubset(mirrored, reverse) {
+			t.Errorf("%s: expected the permuted output set to be a subset", name)
+		}
+	}
+}
+
+func TestDenseWidths(t *testing.T) {
+	narrow, wide := NewEmptyDense(4), NewEmptyDense(8)
+	narrow.Add(0b0011)
+	wide.Add(0b0011)
+	if !wide.IsSubset(narrow, nil) || !narrow.IsSubset(wide, nil) {
+		t.Error("expected sets with the same sequences to be subsets of each other regardless of their channels")
+	}
+
+	wide.Add(0b1000_0001)
+	if wide.IsSubset(narrow, nil) {
+		t.Error("expected a sequence beyond the channels of the other set to be missing from it")
+	}
+}
+
+// BenchmarkOutputSetOfNetwork compares deriving the output set of a network from a populated set with evaluating the
+// network for all inputs by sortnet.BitSlicedOutputSet.
+func BenchmarkOutputSetOfNetwork(b *testing.B) {
+	const channels = 12
+	network := sortnet.NewBatcherOddEvenMergeSort(channels)
+	prefix, _ := sortnet.NewComparatorNetwork(channels, network.Comparators()[:network.Len()/2]...)
+
+	b.Run("derive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NewPartitionedOrdered(channels).Derive(prefix)
+		}
+	})
+	b.Run("bitsliced", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sortnet.BitSlicedOutputSet(NewEmptyPartitionedOrdered(), prefix, channels)
+		}
+	})
+}
+
+func TestEncoding(t *testing.T) {
+	const channels = 6
+	// decoding into a zero value takes the channels from the encoding
+	implementations := map[string]func() sortnet.OutputSet{
+		"partitioned-ordered":   func() sortnet.OutputSet { return NewEmptyPartitionedOrdered() },
+		"partitioned-unordered": NewEmptyPartitionedUnordered,
+		"unordered":             NewEmptyUnordered,
+		"warhol":                func() sortnet.OutputSet { return &Warhol{} },
+		"dense":                 func() sortnet.OutputSet { return &Dense{} },
+	}
+
+	network := sortnet.NewBatcherOddEvenMergeSort(channels)
+	prefix, _ := sortnet.NewComparatorNetwork(channels, network.Comparators()[:4]...)
+	sets := map[string]sortnet.OutputSet{
+		"partitioned-ordered":   NewPartitionedOrdered(channels).Derive(prefix),
+		"partitioned-unordered": NewPartitionedUnordered(channels).Derive(prefix),
+		"unordered":             NewUnordered(channels).Derive(prefix),
+		"warhol":                NewWarhol(channels).Derive(prefix),
+		"dense":                 NewDense(channels).Derive(prefix),
+	}
+
+	for from, newFrom := range implementations {
+		set := sets[from]
+		data, err := set.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: %v", from, err)
+		}
+
+		for to, newTo := range implementations {
+			decoded := newTo()
+			if err = decoded.UnmarshalBinary(data); err != nil {
+				t.Errorf("%s to %s: %v", from, to, err)
+				continue
+			}
+			if decoded.Size() != set.Size() {
+				t.Errorf("%s to %s: expected %d sequences, got %d", from, to, set.Size(), decoded.Size())
+			}
+			for _, seq := range set.Elements() {
+				if !decoded.Contains(seq) {
+					t.Errorf("%s to %s: expected %b in the decoded set", from, to, seq)
+				}
+			}
+
+			md, expected := decoded.Metadata(), set.Metadata()
+			for p := range expected.PartitionSizes {
+				if md.PartitionSizes[p] != expected.PartitionSizes[p] || md.OnesMasks[p] != expected.OnesMasks[p] {
+					t.Errorf("%s to %s: metadata of partition %d differs", from, to, p)
+				}
+			}
+		}
+
+		// a set that does not use the top channels still encodes the channels it was created with
+		narrow := set.NewEmpty()
+		narrow.Add(0b01)
+		narrow.Add(0b11)
+		narrowData, err := narrow.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: %v", from, err)
+		}
+		for to, newTo := range implementations {
+			decoded := newTo()
+			if err = decoded.UnmarshalBinary(narrowData); err != nil {
+				t.Errorf("%s to %s: %v", from, to, err)
+				continue
+			}
+			if reencoded, _ := decoded.MarshalBinary(); string(reencoded) != string(narrowData) {
+				t.Errorf("%s to %s: expected the set to keep its %d channels", from, to, channels)
+			}
+		}
+
+		for _, malformed := range [][]byte{nil, {2}, data[:len(data)-1], append(data, 0)} {
+			if err = newFrom().UnmarshalBinary(malformed); !errors.Is(err, ErrEncoding) {
+				t.Errorf("%s: expected a malformed encoding error for %v, got %v", from, malformed, err)
+			}
+		}
+	}
+}
@@ -10,13 +10,18 @@ func NewEmptyPartitionedOrdered() *PartitionedOrdered {
 }
 
 func NewPartitionedOrdered(channels int) sortnet.OutputSet {
-	return sortnet.PopulateOutputSet(NewEmptyPartitionedOrdered(), channels)
+	set := NewEmptyPartitionedOrdered()
+	set.channels = channels
+	return sortnet.PopulateOutputSet(set, channels)
 }
 
+// PartitionedOrdered keeps its channels as Unordered does.
 type PartitionedOrdered struct {
 	Partitions []map[sortnet.BinarySequence]struct{}
 	Sequences  [][]sortnet.BinarySequence
 	*sortnet.SetMetadata
+
+	channels int
 }
 
 func (s *PartitionedOrdered) Metadata() *sortnet.SetMetadata {
@@ -51,7 +56,7 @@ func (s *PartitionedOrdered) Add(seq sortnet.BinarySequence) {
 }
 
 func (s *PartitionedOrdered) Derive(network sortnet.Network) sortnet.OutputSet {
-	output := NewEmptyPartitionedOrdered()
+	output := s.NewEmpty()
 
 	for _, partition := range s.Sequences {
 		for _, seq := range partition {
@@ -69,6 +74,20 @@ func (s *PartitionedOrdered) PartitionSize(p int) int {
 	return s.SetMetadata.PartitionSizes[p]
 }
 
+func (s *PartitionedOrdered) Elements() []sortnet.BinarySequence {
+	elements := make([]sortnet.BinarySequence, 0, s.Size())
+	for _, partition := range s.Sequences {
+		elements = append(elements, partition...)
+	}
+	return elements
+}
+
+func (s *PartitionedOrdered) NewEmpty() sortnet.OutputSet {
+	set := NewEmptyPartitionedOrdered()
+	set.channels = s.channels
+	return set
+}
+
 func (s *PartitionedOrdered) IsSubset(other sortnet.OutputSet, permutation sortnet.PermutationMap) bool {
 	for pi := range s.Sequences {
 		for _, seq := range s.Sequences[pi] {
@@ -10,12 +10,17 @@ func NewEmptyPartitionedUnordered() sortnet.OutputSet {
 }
 
 func NewPartitionedUnordered(channels int) sortnet.OutputSet {
-	return sortnet.PopulateOutputSet(NewEmptyPartitionedUnordered(), channels)
+	set := NewEmptyPartitionedUnordered().(*PartitionedUnordered)
+	set.channels = channels
+	return sortnet.PopulateOutputSet(set, channels)
 }
 
+// PartitionedUnordered keeps its channels as Unordered does.
 type PartitionedUnordered struct {
 	Partitions [][]sortnet.BinarySequence
 	*sortnet.SetMetadata
+
+	channels int
 }
 
 func (s *PartitionedUnordered) Metadata() *sortnet.SetMetadata {
@@ -50,7 +55,7 @@ func (s *PartitionedUnordered) Add(seq sortnet.BinarySequence) {
 }
 
 func (s *PartitionedUnordered) Derive(network sortnet.Network) sortnet.OutputSet {
-	output := NewEmptyPartitionedUnordered()
+	output := s.NewEmpty()
 
 	for _, partition := range s.Partitions {
 		for _, seq := range partition {
@@ -76,6 +81,20 @@ func (s *PartitionedUnordered) PartitionSize(p int) int {
 	return len(s.Partitions[p])
 }
 
+func (s *PartitionedUnordered) Elements() []sortnet.BinarySequence {
+	elements := make([]sortnet.BinarySequence, 0, s.Size())
+	for _, partition := range s.Partitions {
+		elements = append(elements, partition...)
+	}
+	return elements
+}
+
+func (s *PartitionedUnordered) NewEmpty() sortnet.OutputSet {
+	set := NewEmptyPartitionedUnordered().(*PartitionedUnordered)
+	set.channels = s.channels
+	return set
+}
+
 func (s *PartitionedUnordered) IsSubset(other sortnet.OutputSet, permutationMap sortnet.PermutationMap) bool {
 	for p := range s.Partitions {
 		for _, seq := range s.Partitions[p] {
@@ -10,12 +10,18 @@ func NewEmptyUnordered() sortnet.OutputSet {
 }
 
 func NewUnordered(channels int) sortnet.OutputSet {
-	return sortnet.PopulateOutputSet(NewEmptyUnordered(), channels)
+	set := NewEmptyUnordered().(*Unordered)
+	set.channels = channels
+	return sortnet.PopulateOutputSet(set, channels)
 }
 
+// Unordered keeps the channels it was created with, and passes them on to the sets created from it, such that its
+// encoding holds them. A set created by NewEmptyUnordered has none, and takes them from its sequences when encoded.
 type Unordered struct {
 	Sequences []sortnet.BinarySequence
 	*sortnet.SetMetadata
+
+	channels int
 }
 
 func (s *Unordered) Metadata() *sortnet.SetMetadata {
@@ -43,7 +49,7 @@ func (s *Unordered) Add(seq sortnet.BinarySequence) {
 }
 
 func (s *Unordered) Derive(network sortnet.Network) sortnet.OutputSet {
-	output := NewEmptyUnordered()
+	output := s.NewEmpty()
 
 	for i := range s.Sequences {
 		output.Add(network.Transform(s.Sequences[i]))
@@ -55,6 +61,18 @@ func (s *Unordered) Size() int {
 	return s.SetMetadata.Size
 }
 
+func (s *Unordered) Elements() []sortnet.BinarySequence {
+	elements := make([]sortnet.BinarySequence, len(s.Sequences))
+	copy(elements, s.Sequences)
+	return elements
+}
+
+func (s *Unordered) NewEmpty() sortnet.OutputSet {
+	set := NewEmptyUnordered().(*Unordered)
+	set.channels = s.channels
+	return set
+}
+
 func (s *Unordered) IsSubset(other sortnet.OutputSet, permutationMap sortnet.PermutationMap) bool {
 	for _, seq := range s.Sequences {
 		if permutationMap != nil {
@@ -1,11 +1,22 @@
 package outputset
 
 import (
+	"fmt"
+	"math/bits"
+
 	"github.com/andersfylling/go-sortnet/sortnet"
 	"github.com/kelindar/bitmap"
 )
 
+// MaxWarholChannels is the most channels a Warhol set supports, as its bitmaps are indexed by uint32.
+const MaxWarholChannels = 32
+
+// NewEmptyWarhol panics when channels exceeds MaxWarholChannels.
 func NewEmptyWarhol(channels int) *Warhol {
+	if channels > MaxWarholChannels {
+		panic(fmt.Sprintf("warhol sets support at most %d channels, got %d", MaxWarholChannels, channels))
+	}
+
 	set := &Warhol{
 		SetMetadata: &sortnet.SetMetadata{},
 	}
@@ -23,7 +34,7 @@ func NewWarhol(channels int) sortnet.OutputSet {
 // Warhol is optimized for applying permutations. Instead of having a list of binary sequences, we have a list per
 // channel which contains a bitmap. Each offset represents a sequence. This means we only need to apply a permutation
 // once, and all binary sequences reflect the new order. This is a optimized subsumption check, all other operations
-// are in return much slower.
+// are in return much slower. The bitmaps are indexed by uint32, so at most MaxWarholChannels are supported.
 //
 // Status: failure. After a permutation is applied the sequences are no longer in sorted order, meaning we can't
 //
@@ -69,14 +80,22 @@ func (s *Warhol) Add(seq sortnet.BinarySequence) {
 
 func (s *Warhol) Derive(network sortnet.Network) sortnet.OutputSet {
 	output := NewEmptyWarhol(len(s.Channels))
+	s.each(func(seq sortnet.BinarySequence) {
+		output.Add(network.Transform(seq))
+	})
+	return output
+}
 
-	mask := sortnet.SequenceMask(len(s.Channels))
-	for target := sortnet.BinarySequence(1); target < mask; target++ {
-		if s.Contains(target) {
-			output.Add(network.Transform(target))
-		}
+// each calls fn for every sequence in the set. A sequence is only set in the bitmaps of its own channels, so the
+// sequences are found in the bitmap of their lowest channel without testing every possible sequence.
+func (s *Warhol) each(fn func(seq sortnet.BinarySequence)) {
+	for channel := range s.Channels {
+		s.Channels[channel].Range(func(x uint32) {
+			if bits.TrailingZeros32(x) == channel {
+				fn(sortnet.BinarySequence(x))
+			}
+		})
 	}
-	return output
 }
 
 func (s *Warhol) Size() int {
@@ -97,6 +116,18 @@ func bitmapSubsumes(left, right bitmap.Bitmap) bool {
 	return true
 }
 
+func (s *Warhol) Elements() []sortnet.BinarySequence {
+	elements := make([]sortnet.BinarySequence, 0, s.Size())
+	s.each(func(seq sortnet.BinarySequence) {
+		elements = append(elements, seq)
+	})
+	return elements
+}
+
+func (s *Warhol) NewEmpty() sortnet.OutputSet {
+	return NewEmptyWarhol(len(s.Channels))
+}
+
 func (s *Warhol) IsSubset(otherA sortnet.OutputSet, permutation sortnet.PermutationMap) bool {
 	other := otherA.(*Warhol)
 
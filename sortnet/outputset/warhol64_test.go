@@ -0,0 +1,37 @@
+//go:build sortnet64
+
+package outputset
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+func TestWarholChannels(t *testing.T) {
+	set := NewEmptyWarhol(MaxWarholChannels)
+	set.Add(1<<31 | 1)
+	if set.Contains(1) || !set.Contains(1<<31|1) {
+		t.Error("expected sequences of 32 channels to be kept apart")
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected a panic for more channels than a warhol set supports")
+			}
+		}()
+		NewEmptyWarhol(MaxWarholChannels + 1)
+	}()
+
+	unordered := NewEmptyUnordered()
+	unordered.Add(sortnet.BinarySequence(1) << 39)
+	data, err := unordered.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Warhol{}).UnmarshalBinary(data); !errors.Is(err, ErrEncoding) {
+		t.Errorf("expected an encoding error for 40 channels, got %v", err)
+	}
+}
@@ -24,3 +24,24 @@ func ApplyPermutation(src BinarySequence, permutation PermutationMap) BinarySequ
 type PermutationGeneratorHook = func(PermutationMap) bool
 
 type GeneratePermutationsFunc = func(channels int, dst, src *SetMetadata, hook PermutationGeneratorHook) bool
+
+// GenerateSubsetPermutationsFunc generates the permutations of src that make src a subset of dst. Unlike a
+// GeneratePermutationsFunc, which only sees the metadata of the sets, it can test the sets themselves.
+type GenerateSubsetPermutationsFunc = func(channels int, src, dst OutputSet, hook PermutationGeneratorHook) bool
+
+// TestedPermutations turns a generator of candidate permutations into a GenerateSubsetPermutationsFunc, by passing on
+// the candidates that make src a subset of dst.
+func TestedPermutations(generate GeneratePermutationsFunc) GenerateSubsetPermutationsFunc {
+	return func(channels int, src, dst OutputSet, hook PermutationGeneratorHook) bool {
+		return generate(channels, src.Metadata(), dst.Metadata(), func(permutationMap PermutationMap) bool {
+			return src.IsSubset(dst, permutationMap) && hook(permutationMap)
+		})
+	}
+}
+
+// SubsetPermutationGenerators holds the subset permutation generators by name, such that they can be chosen on the
+// command line and by workers.
+var SubsetPermutationGenerators = map[string]GenerateSubsetPermutationsFunc{
+	"bitmap": TestedPermutations(GeneratePermutationsByBitmap),
+	"subset": GeneratePermutationsBySubset,
+}
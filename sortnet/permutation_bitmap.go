@@ -57,6 +57,92 @@ func QuickValidatePermutationBitMapPositions(channels int, constraints []BinaryS
 	return output == SequenceMask(channels)
 }
 
+// MatchPermutationBitMapPositions finds a permutation where every position is moved to one of its legal positions,
+// which is a perfect matching between the positions and their legal positions. By Hall's theorem it exists unless
+// some k positions together have fewer than k legal positions.
+func MatchPermutationBitMapPositions(channels int, constraints []BinarySequence) (PermutationMap, bool) {
+	// holders[target] is the position moved to target so far, or -1
+	holders := make([]int, channels)
+	for i := range holders {
+		holders[i] = -1
+	}
+
+	// augment moves position to a free target, possibly moving the holder of a target to another one
+	var visited BinarySequence
+	var augment func(position int) bool
+	augment = func(position int) bool {
+		for it := NewSequenceIterator(constraints[position]); !it.Empty(); {
+			target := it.Next()
+			bit := BinarySequence(0b1 << target)
+			if visited&bit != 0 {
+				continue
+			}
+			visited |= bit
+
+			if holders[target] < 0 || augment(holders[target]) {
+				holders[target] = position
+				return true
+			}
+		}
+		return false
+	}
+
+	for position := 0; position < channels; position++ {
+		visited = 0
+		if !augment(position) {
+			return nil, false
+		}
+	}
+
+	permutation := make(PermutationMap, channels)
+	for target, position := range holders {
+		permutation[position] = target
+	}
+	return permutation, true
+}
+
+// PropagatePermutationBitMapPositions removes every legal position that no permutation satisfying all the constraints
+// uses, as the positions must all be moved to different positions. Returns false when no such permutation exists.
+//
+// Following Régin, a position i can only move to a target held by position j in a perfect matching when j can reach
+// i again through other legal positions, as the targets can then be rotated along the cycle.
+func PropagatePermutationBitMapPositions(channels int, constraints []BinarySequence) bool {
+	permutation, ok := MatchPermutationBitMapPositions(channels, constraints)
+	if !ok {
+		return false
+	}
+
+	holders := make([]int, channels)
+	for position, target := range permutation {
+		holders[target] = position
+	}
+
+	// reach[i] holds the positions whose target i can take, directly or by moving further positions along
+	reach := make([]BinarySequence, channels)
+	for position := range reach {
+		for it := NewSequenceIterator(constraints[position]); !it.Empty(); {
+			reach[position] |= 0b1 << holders[it.Next()]
+		}
+	}
+	for k := range reach {
+		for i := range reach {
+			if reach[i]&(0b1<<k) != 0 {
+				reach[i] |= reach[k]
+			}
+		}
+	}
+
+	for position := range constraints {
+		for it := NewSequenceIterator(constraints[position]); !it.Empty(); {
+			target := it.Next()
+			if reach[holders[target]]&(0b1<<position) == 0 {
+				constraints[position] &^= 0b1 << target
+			}
+		}
+	}
+	return true
+}
+
 // GeneratePermutationsByBitmap will identify all plausible permutations using backtracking.
 func GeneratePermutationsByBitmap(channels int, src, dst *SetMetadata, hook PermutationGeneratorHook) bool {
 	reject := func(permutationMap PermutationMap) bool {
@@ -74,7 +160,8 @@ func GeneratePermutationsByBitmap(channels int, src, dst *SetMetadata, hook Perm
 	}
 
 	constraints := PermutationBitMapPositions(channels, dst, src)
-	if !QuickValidatePermutationBitMapPositions(channels, constraints) {
+	if !QuickValidatePermutationBitMapPositions(channels, constraints) ||
+		!PropagatePermutationBitMapPositions(channels, constraints) {
 		return false
 	}
 
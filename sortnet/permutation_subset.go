@@ -0,0 +1,112 @@
+package sortnet
+
+// GeneratePermutationsBySubset identifies the permutations of src that make src a subset of dst, using backtracking
+// over the bitmap constraints. Unlike GeneratePermutationsByBitmap it tests partial permutations against the sets: a
+// sequence of src is mapped as soon as its remaining unmapped bits are either all zeros or all ones, as those can only
+// end up at the remaining unused positions. The search backtracks as soon as a mapped sequence is missing from dst,
+// so every permutation passed to the hook already makes src a subset of dst.
+//
+// The permutation is built in place, and the hook must copy it to keep it.
+func GeneratePermutationsBySubset(channels int, src, dst OutputSet, hook PermutationGeneratorHook) bool {
+	constraints := PermutationBitMapPositions(channels, dst.Metadata(), src.Metadata())
+	if !QuickValidatePermutationBitMapPositions(channels, constraints) ||
+		!PropagatePermutationBitMapPositions(channels, constraints) {
+		return false
+	}
+
+	// fix the most constrained positions first, as they branch the least
+	order := make([]int, channels)
+	for i := range order {
+		order[i] = i
+	}
+	for i := 1; i < channels; i++ {
+		for j := i; j > 0 && constraints[order[j]].OnesCount() < constraints[order[j-1]].OnesCount(); j-- {
+			order[j], order[j-1] = order[j-1], order[j]
+		}
+	}
+
+	// group the sequences by the step after which they can be mapped
+	elements := src.Elements()
+	levels := make([]int, channels+1)
+	for _, seq := range elements {
+		levels[mappedAfter(seq, order)+1]++
+	}
+	for step := 1; step <= channels; step++ {
+		levels[step] += levels[step-1]
+	}
+	sequences := make([]BinarySequence, len(elements))
+	offsets := append([]int{}, levels[:channels]...)
+	for _, seq := range elements {
+		step := mappedAfter(seq, order)
+		sequences[offsets[step]] = seq
+		offsets[step]++
+	}
+
+	mask := SequenceMask(channels)
+	permutation := make(PermutationMap, channels)
+	options := make([]BinarySequence, channels)
+	var mapped, used BinarySequence
+
+	consistent := func(step int) bool {
+		for _, seq := range sequences[levels[step]:levels[step+1]] {
+			image := ApplyPermutation(seq&mapped, permutation)
+			if seq&^mapped != 0 {
+				image |= mask &^ used
+			}
+			if !dst.Contains(image) {
+				return false
+			}
+		}
+		return true
+	}
+
+	step := 0
+	options[0] = constraints[order[0]]
+	for step >= 0 {
+		position := order[step]
+		if options[step] == 0 {
+			// every target was tried, undo the previous step
+			step--
+			if step >= 0 {
+				mapped ^= 0b1 << order[step]
+				used ^= 0b1 << permutation[order[step]]
+			}
+			continue
+		}
+
+		target := options[step].MostSignificantBitOffset()
+		options[step] ^= 0b1 << target
+		permutation[position] = target
+		mapped |= 0b1 << position
+		used |= 0b1 << target
+
+		switch {
+		case !consistent(step):
+		case step == channels-1:
+			if hook(permutation) {
+				return true
+			}
+		default:
+			step++
+			options[step] = constraints[order[step]] &^ used
+			continue
+		}
+
+		mapped ^= 0b1 << position
+		used ^= 0b1 << target
+	}
+
+	return false
+}
+
+// mappedAfter returns the first step of the order after which the positions left to map hold equal bits of seq.
+func mappedAfter(seq BinarySequence, order []int) int {
+	last := len(order) - 1
+	bit := (seq >> order[last]) & 0b1
+
+	step := last
+	for step > 0 && (step == last || (seq>>order[step])&0b1 == bit) {
+		step--
+	}
+	return step
+}
@@ -6,4 +6,64 @@ func TestFunctionSignatures(t *testing.T) {
 	_ = []GeneratePermutationsFunc{
 		GeneratePermutationsByBitmap,
 	}
+	_ = []GenerateSubsetPermutationsFunc{
+		GeneratePermutationsBySubset,
+		TestedPermutations(GeneratePermutationsByBitmap),
+	}
+}
+
+func TestPropagatePermutationBitMapPositions(t *testing.T) {
+	const channels = 4
+
+	var permutations []PermutationMap
+	for p := 0; p < 4*4*4*4; p++ {
+		permutation := PermutationMap{p & 3, p >> 2 & 3, p >> 4 & 3, p >> 6 & 3}
+		var used BinarySequence
+		for _, target := range permutation {
+			used |= 0b1 << target
+		}
+		if used == SequenceMask(channels) {
+			permutations = append(permutations, permutation)
+		}
+	}
+
+	// every combination of legal positions, compared with the permutations satisfying them
+	for c := 0; c < 1<<(channels*channels); c++ {
+		constraints := make([]BinarySequence, channels)
+		for i := range constraints {
+			constraints[i] = BinarySequence(c>>(i*channels)) & SequenceMask(channels)
+		}
+
+		legal := make([]BinarySequence, channels)
+		var feasible bool
+		for _, permutation := range permutations {
+			satisfies := true
+			for i, target := range permutation {
+				satisfies = satisfies && constraints[i]&(0b1<<target) != 0
+			}
+			if !satisfies {
+				continue
+			}
+			feasible = true
+			for i, target := range permutation {
+				legal[i] |= 0b1 << target
+			}
+		}
+
+		if _, ok := MatchPermutationBitMapPositions(channels, constraints); ok != feasible {
+			t.Fatalf("%v: expected a matching to exist: %t, got %t", constraints, feasible, ok)
+		}
+		propagated := append([]BinarySequence{}, constraints...)
+		if ok := PropagatePermutationBitMapPositions(channels, propagated); ok != feasible {
+			t.Fatalf("%v: expected propagation to succeed: %t, got %t", constraints, feasible, ok)
+		}
+		if !feasible {
+			continue
+		}
+		for i := range legal {
+			if propagated[i] != legal[i] {
+				t.Fatalf("%v: expected %v after propagation, got %v", constraints, legal, propagated)
+			}
+		}
+	}
 }
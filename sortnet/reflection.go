@@ -0,0 +1,37 @@
+package sortnet
+
+import "math/bits"
+
+// Reflect reverses the order of the channels of the sequence and complements every bit. A sorted sequence stays
+// sorted, and reflecting twice gives the original sequence.
+func Reflect(seq BinarySequence, channels int) BinarySequence {
+	complement := ^seq & SequenceMask(channels)
+	return BinarySequence(bits.Reverse64(uint64(complement)) >> (64 - channels))
+}
+
+// ReflectOutputSet creates the output set of the reflected network, from the output set of the network. The metadata
+// of the returned set matches the reflected sequences, where partition k of the set becomes partition channels-k.
+func ReflectOutputSet(set OutputSet, channels int) OutputSet {
+	reflected := set.NewEmpty()
+	for _, seq := range set.Elements() {
+		reflected.Add(Reflect(seq, channels))
+	}
+
+	return reflected
+}
+
+// Reflect returns the network with its channels in reverse order. A comparator from channel j to i becomes one from
+// channels-1-i to channels-1-j, and the reflected network sorts if and only if the network does.
+func (n *ComparatorNetwork) Reflect() *ComparatorNetwork {
+	channels := n.Channels()
+	reflected := &ComparatorNetwork{
+		channels:    n.channels,
+		comparators: make([]Comparator, len(n.comparators)),
+		layers:      n.layers,
+	}
+	for i, comparator := range n.comparators {
+		reflected.comparators[i] = Comparator{From: channels - 1 - comparator.To, To: channels - 1 - comparator.From}
+	}
+
+	return reflected
+}
@@ -0,0 +1,43 @@
+package sortnet
+
+import "testing"
+
+func TestReflect(t *testing.T) {
+	const channels = 6
+
+	for seq := BinarySequence(0); seq <= SequenceMask(channels); seq++ {
+		if Reflect(Reflect(seq, channels), channels) != seq {
+			t.Errorf("expected reflecting %b twice to give the sequence", seq)
+		}
+		sorted := seq&(seq+1) == 0
+		if reflected := Reflect(seq, channels); sorted != (reflected&(reflected+1) == 0) {
+			t.Errorf("expected %b and its reflection %b to be equally sorted", seq, reflected)
+		}
+	}
+
+	network := NewBatcherOddEvenMergeSort(channels)
+	prefix, _ := NewComparatorNetwork(channels, network.Comparators()[:6]...)
+	reflected := prefix.Reflect()
+
+	outputs := func(n *ComparatorNetwork) map[BinarySequence]bool {
+		set := map[BinarySequence]bool{}
+		for seq := BinarySequence(0); seq <= SequenceMask(channels); seq++ {
+			set[n.Transform(seq)] = true
+		}
+		return set
+	}
+
+	expected, got := outputs(prefix), outputs(reflected)
+	if len(expected) != len(got) {
+		t.Fatalf("expected %d outputs of the reflected network, got %d", len(expected), len(got))
+	}
+	for seq := range expected {
+		if !got[Reflect(seq, channels)] {
+			t.Errorf("expected the reflection of %b among the outputs of the reflected network", seq)
+		}
+	}
+
+	if ok, _ := IsSortingNetwork(network.Reflect(), channels); !ok {
+		t.Error("expected the reflection of a sorting network to sort")
+	}
+}
@@ -0,0 +1,90 @@
+package sat
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var ErrUnsatisfiable = errors.New("no sorting network exists for the problem")
+
+// WriteDIMACS writes the formula in the DIMACS CNF format understood by most SAT solvers.
+func (f *Formula) WriteDIMACS(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "c sorting network with %d channels, %d comparators, depth %d\n",
+		f.problem.Channels, f.problem.Comparators, f.problem.Depth)
+	if f.problem.Prefix != nil {
+		text, _ := f.problem.Prefix.MarshalText()
+		fmt.Fprintf(bw, "c prefix %s\n", text)
+	}
+	fmt.Fprintf(bw, "p cnf %d %d\n", f.Variables, len(f.Clauses))
+
+	for _, clause := range f.Clauses {
+		for _, literal := range clause {
+			fmt.Fprintf(bw, "%d ", literal)
+		}
+		bw.WriteString("0\n")
+	}
+
+	return bw.Flush()
+}
+
+// ReadModel reads the output of a SAT solver in the format used by the SAT competitions: a status line "s
+// SATISFIABLE" followed by value lines "v 1 -2 3 ... 0". Lines starting with "c" are ignored, and the status and
+// value prefixes are optional, as some solvers print the bare model. The model is indexed by variable, where index 0
+// is unused, and is ready for Formula.Decode. ErrUnsatisfiable is returned when the solver reports so, and an error
+// for any other status, such as UNKNOWN after a timeout, or when the output holds no values.
+func ReadModel(r io.Reader, variables int) ([]bool, error) {
+	model := make([]bool, variables+1)
+	var values bool
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "", strings.HasPrefix(line, "c"):
+			continue
+		case strings.HasPrefix(line, "s "), line == "SAT", line == "UNSAT", line == "SATISFIABLE", line == "UNSATISFIABLE":
+			switch status := strings.TrimSpace(strings.TrimPrefix(line, "s ")); status {
+			case "SAT", "SATISFIABLE":
+				continue
+			case "UNSAT", "UNSATISFIABLE":
+				return nil, ErrUnsatisfiable
+			default:
+				return nil, fmt.Errorf("solver did not find a model, status %s", status)
+			}
+		}
+
+		for _, field := range strings.Fields(strings.TrimPrefix(line, "v ")) {
+			literal, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid literal %q", field)
+			}
+			if literal == 0 {
+				continue
+			}
+
+			variable := literal
+			if variable < 0 {
+				variable = -variable
+			}
+			if variable > variables {
+				return nil, fmt.Errorf("literal %d is outside of %d variables", literal, variables)
+			}
+			model[variable] = literal > 0
+			values = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !values {
+		return nil, errors.New("no model in the solver output")
+	}
+
+	return model, nil
+}
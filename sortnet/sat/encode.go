@@ -0,0 +1,247 @@
+// Package sat encodes the existence of a sorting network with a given size or depth as a boolean satisfiability
+// problem, such that hard instances can be handed to a SAT solver instead of the generate-and-prune search.
+package sat
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+// Problem asks whether a sorting network exists with the given number of comparators or layers, optionally starting
+// with a given prefix. Exactly one of Comparators and Depth must be set, and describes the part after the prefix.
+type Problem struct {
+	Channels    int
+	Comparators int
+	Depth       int
+
+	// Prefix is the network every solution starts with. Only the output set of the prefix has to be sorted by the
+	// remaining comparators, which makes the formula much smaller. Optional.
+	Prefix *sortnet.ComparatorNetwork
+}
+
+func (p *Problem) validate() error {
+	if p.Channels < 1 || p.Channels > sortnet.MaxChannels {
+		return fmt.Errorf("channels must be in the range [1, %d], got %d", sortnet.MaxChannels, p.Channels)
+	}
+	if (p.Comparators > 0) == (p.Depth > 0) {
+		return errors.New("exactly one of comparators and depth must be above 0")
+	}
+	if p.Prefix != nil && p.Prefix.Channels() > p.Channels {
+		return fmt.Errorf("prefix uses %d channels, expected at most %d", p.Prefix.Channels(), p.Channels)
+	}
+
+	return nil
+}
+
+// Inputs returns the unsorted binary sequences the remaining comparators must sort, following the 0-1 principle.
+// Without a prefix that is every unsorted sequence, otherwise it is the unsorted part of the prefix output set.
+func (p *Problem) Inputs() []sortnet.BinarySequence {
+	var network sortnet.Network = &sortnet.ComparatorNetwork{}
+	if p.Prefix != nil {
+		network = p.Prefix
+	}
+
+	var inputs []sortnet.BinarySequence
+	seen := map[sortnet.BinarySequence]bool{}
+	mask := sortnet.SequenceMask(p.Channels)
+	for input := sortnet.BinarySequence(0); ; input++ {
+		output := network.Transform(input)
+		if !output.IsSorted() && !seen[output] {
+			seen[output] = true
+			inputs = append(inputs, output)
+		}
+
+		if input == mask {
+			break
+		}
+	}
+
+	return inputs
+}
+
+// gate is the variable deciding whether a comparator is placed at a given step.
+type gate struct {
+	comparator sortnet.Comparator
+	variable   int
+}
+
+// Formula is a CNF encoding of a Problem. Variables are numbered from 1, and a clause holds the variable for a positive
+// literal and the negated variable for a negative literal, as in the DIMACS format.
+//
+// A step is a single comparator when searching by size, or a layer of comparators when searching by depth. Every
+// step has a gate variable per possible comparator, and for every input a variable per channel holding its value
+// after the step. The values of the last step are fixed to the sorted output of each input.
+type Formula struct {
+	Variables int
+	Clauses   [][]int
+
+	problem Problem
+	steps   [][]gate
+}
+
+// Encode creates the CNF formula that is satisfiable if and only if the sorting network described by the problem
+// exists.
+func Encode(problem Problem) (*Formula, error) {
+	if err := problem.validate(); err != nil {
+		return nil, err
+	}
+
+	f := &Formula{problem: problem}
+
+	steps := problem.Comparators
+	if problem.Depth > 0 {
+		steps = problem.Depth
+	}
+	if problem.Channels < 2 {
+		// no comparator fits, and the empty network already sorts
+		steps = 0
+	}
+
+	comparators := sortnet.AllComparatorCombinations(problem.Channels)
+	used := make([][]int, steps)
+	for s := 0; s < steps; s++ {
+		gates := make([]gate, 0, len(comparators))
+		for _, comparator := range comparators {
+			gates = append(gates, gate{comparator: comparator, variable: f.newVariable()})
+		}
+		f.steps = append(f.steps, gates)
+
+		used[s] = f.encodeUsedChannels(gates)
+		if problem.Depth > 0 {
+			f.encodeLayer(gates)
+		} else {
+			f.encodeSingleComparator(gates)
+		}
+	}
+
+	for _, input := range problem.Inputs() {
+		values := make([]int, problem.Channels)
+		for channel := range values {
+			values[channel] = f.newVariable()
+			if (input>>channel)&0b1 == 1 {
+				f.addClause(values[channel])
+			} else {
+				f.addClause(-values[channel])
+			}
+		}
+
+		for s := 0; s < steps; s++ {
+			values = f.encodeStep(f.steps[s], used[s], values)
+		}
+
+		ones := input.OnesCount()
+		for channel, value := range values {
+			if channel < ones {
+				f.addClause(value)
+			} else {
+				f.addClause(-value)
+			}
+		}
+	}
+
+	return f, nil
+}
+
+func (f *Formula) newVariable() int {
+	f.Variables++
+	return f.Variables
+}
+
+func (f *Formula) addClause(literals ...int) {
+	f.Clauses = append(f.Clauses, literals)
+}
+
+// encodeUsedChannels creates a variable per channel that is true exactly when a gate touching the channel is set.
+func (f *Formula) encodeUsedChannels(gates []gate) []int {
+	used := make([]int, f.problem.Channels)
+	for channel := range used {
+		used[channel] = f.newVariable()
+
+		anyGate := []int{-used[channel]}
+		for _, g := range gates {
+			if g.comparator.From == channel || g.comparator.To == channel {
+				anyGate = append(anyGate, g.variable)
+				f.addClause(-g.variable, used[channel])
+			}
+		}
+		f.addClause(anyGate...)
+	}
+
+	return used
+}
+
+// encodeSingleComparator places exactly one comparator in the step. Using exactly instead of at most one comparator
+// does not change the answer, as appending a comparator to a sorting network keeps it sorting.
+func (f *Formula) encodeSingleComparator(gates []gate) {
+	atLeastOne := make([]int, 0, len(gates))
+	for i := range gates {
+		atLeastOne = append(atLeastOne, gates[i].variable)
+		for j := i + 1; j < len(gates); j++ {
+			f.addClause(-gates[i].variable, -gates[j].variable)
+		}
+	}
+	f.addClause(atLeastOne...)
+}
+
+// encodeLayer allows any matching of comparators in the step, by using every channel at most once.
+func (f *Formula) encodeLayer(gates []gate) {
+	for i := range gates {
+		for j := i + 1; j < len(gates); j++ {
+			a, b := gates[i].comparator, gates[j].comparator
+			if a.From == b.From || a.From == b.To || a.To == b.From || a.To == b.To {
+				f.addClause(-gates[i].variable, -gates[j].variable)
+			}
+		}
+	}
+}
+
+// encodeStep returns the channel values after the step. A comparator moves the set bit from the From channel to the
+// To channel, so To becomes the disjunction and From the conjunction of the two values.
+func (f *Formula) encodeStep(gates []gate, used []int, values []int) []int {
+	next := make([]int, len(values))
+	for channel := range next {
+		next[channel] = f.newVariable()
+
+		// channels not touched by a comparator keep their value
+		f.addClause(used[channel], -values[channel], next[channel])
+		f.addClause(used[channel], values[channel], -next[channel])
+	}
+
+	for _, g := range gates {
+		from, to := g.comparator.From, g.comparator.To
+		f.addClause(-g.variable, -values[from], next[to])
+		f.addClause(-g.variable, -values[to], next[to])
+		f.addClause(-g.variable, values[from], values[to], -next[to])
+
+		f.addClause(-g.variable, -values[from], -values[to], next[from])
+		f.addClause(-g.variable, values[from], -next[from])
+		f.addClause(-g.variable, values[to], -next[from])
+	}
+
+	return next
+}
+
+// Decode turns a satisfying assignment into the sorting network, including the prefix. The model is indexed by
+// variable, where index 0 is unused.
+func (f *Formula) Decode(model []bool) (*sortnet.ComparatorNetwork, error) {
+	if len(model) <= f.Variables {
+		return nil, fmt.Errorf("model holds %d variables, expected %d", len(model)-1, f.Variables)
+	}
+
+	var comparators []sortnet.Comparator
+	if f.problem.Prefix != nil {
+		comparators = f.problem.Prefix.Comparators()
+	}
+
+	for _, gates := range f.steps {
+		for _, g := range gates {
+			if model[g.variable] {
+				comparators = append(comparators, g.comparator)
+			}
+		}
+	}
+
+	return sortnet.NewComparatorNetwork(f.problem.Channels, comparators...)
+}
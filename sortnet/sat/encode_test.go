@@ -0,0 +1,182 @@
+package sat
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+// propagate fixes the gates of the network and derives every other variable by unit propagation, which is enough as
+// the channel values are functions of the gates. It reports whether every clause is satisfied.
+func propagate(f *Formula, network *sortnet.ComparatorNetwork) ([]bool, bool) {
+	assigned := make([]int, f.Variables+1) // 0 unassigned, 1 true, -1 false
+	steps := network.Comparators()
+	if f.problem.Depth > 0 {
+		steps = nil
+	}
+	layers := network.Layers()
+
+	for s, gates := range f.steps {
+		for _, g := range gates {
+			assigned[g.variable] = -1
+			if f.problem.Depth > 0 && s < len(layers) {
+				for _, comparator := range layers[s] {
+					if comparator == g.comparator {
+						assigned[g.variable] = 1
+					}
+				}
+			} else if s < len(steps) && steps[s] == g.comparator {
+				assigned[g.variable] = 1
+			}
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, clause := range f.Clauses {
+			unassigned, satisfied := 0, false
+			var last int
+			for _, literal := range clause {
+				variable, value := literal, 1
+				if literal < 0 {
+					variable, value = -literal, -1
+				}
+				switch assigned[variable] {
+				case value:
+					satisfied = true
+				case 0:
+					unassigned++
+					last = literal
+				}
+			}
+
+			switch {
+			case satisfied:
+			case unassigned == 0:
+				return nil, false
+			case unassigned == 1:
+				if last > 0 {
+					assigned[last] = 1
+				} else {
+					assigned[-last] = -1
+				}
+				changed = true
+			}
+		}
+	}
+
+	model := make([]bool, f.Variables+1)
+	for variable := 1; variable <= f.Variables; variable++ {
+		model[variable] = assigned[variable] == 1
+	}
+	return model, true
+}
+
+func TestEncode(t *testing.T) {
+	sorting, _ := sortnet.ParseComparatorNetwork("[[(0,2),(1,3)],[(0,1),(2,3)],[(1,2)]]")
+	broken, _ := sortnet.ParseComparatorNetwork("[[(0,2),(1,3)],[(0,1),(2,3)],[(0,3)]]")
+
+	for _, problem := range []Problem{{Channels: 4, Comparators: 5}, {Channels: 4, Depth: 3}} {
+		f, err := Encode(problem)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		model, ok := propagate(f, sorting)
+		if !ok {
+			t.Fatalf("%+v: expected the sorting network to satisfy the formula", problem)
+		}
+		if _, ok = propagate(f, broken); ok {
+			t.Errorf("%+v: expected a network that does not sort to falsify the formula", problem)
+		}
+
+		network, err := f.Decode(model)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, _ := sortnet.IsSortingNetwork(network, 4); !ok || network.Channels() != 4 {
+			t.Errorf("%+v: expected the decoded network to sort 4 channels, got %s", problem, network)
+		}
+	}
+}
+
+func TestEncodePrefix(t *testing.T) {
+	prefix, _ := sortnet.ParseComparatorNetwork("[(0,2),(1,3)]")
+	problem := Problem{Channels: 4, Comparators: 3, Prefix: prefix}
+
+	// only the unsorted sequences of the prefix output set remain
+	if n := len(problem.Inputs()); n != 4 {
+		t.Errorf("expected 4 inputs, got %d", n)
+	}
+
+	f, err := Encode(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	suffix, _ := sortnet.ParseComparatorNetwork("[(0,1),(2,3),(1,2)]")
+	model, ok := propagate(f, suffix)
+	if !ok {
+		t.Fatal("expected the suffix to satisfy the formula")
+	}
+
+	network, err := f.Decode(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network.Len() != 5 {
+		t.Errorf("expected the decoded network to include the prefix, got %s", network)
+	}
+
+	invalid := []Problem{
+		{Channels: 0, Comparators: 1},
+		{Channels: 4},
+		{Channels: 4, Comparators: 1, Depth: 1},
+		{Channels: 2, Comparators: 1, Prefix: prefix},
+	}
+	for _, problem := range invalid {
+		if _, err = Encode(problem); err == nil {
+			t.Errorf("expected %+v to be rejected", problem)
+		}
+	}
+}
+
+func TestDIMACS(t *testing.T) {
+	f, err := Encode(Problem{Channels: 3, Comparators: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err = f.WriteDIMACS(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.HasPrefix(lines[1], "p cnf ") || len(lines) != len(f.Clauses)+2 {
+		t.Errorf("unexpected DIMACS output:\n%s", buf.String())
+	}
+
+	model, err := ReadModel(strings.NewReader("c comment\ns SATISFIABLE\nv 1 -2\nv 3 0\n"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !model[1] || model[2] || !model[3] {
+		t.Errorf("unexpected model %v", model)
+	}
+
+	if _, err = ReadModel(strings.NewReader("s UNSATISFIABLE\n"), 3); err != ErrUnsatisfiable {
+		t.Errorf("expected ErrUnsatisfiable, got %v", err)
+	}
+	if _, err = ReadModel(strings.NewReader("v 4 0\n"), 3); err == nil {
+		t.Error("expected a literal outside of the variables to be rejected")
+	}
+	for _, output := range []string{"", "s UNKNOWN\n", "s INDETERMINATE\nv 1 0\n", "s SATISFIABLE\nv 0\n", "c timeout\n"} {
+		if _, err = ReadModel(strings.NewReader(output), 3); err == nil || err == ErrUnsatisfiable {
+			t.Errorf("expected %q to be rejected as holding no model", output)
+		}
+	}
+	if model, err = ReadModel(strings.NewReader("1 -2 3\n"), 3); err != nil || !model[1] {
+		t.Errorf("expected a bare model to be read, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package sat
+
+import (
+	"context"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+// Solve searches for the sorting network using the built-in solver. Returns ErrUnsatisfiable when no such network
+// exists.
+func (f *Formula) Solve(ctx context.Context) (*sortnet.ComparatorNetwork, error) {
+	solver := NewSolver(f.Variables)
+	for _, c := range f.Clauses {
+		if !solver.AddClause(c...) {
+			return nil, ErrUnsatisfiable
+		}
+	}
+
+	satisfiable, err := solver.Solve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !satisfiable {
+		return nil, ErrUnsatisfiable
+	}
+
+	return f.Decode(solver.Model())
+}
@@ -0,0 +1,582 @@
+package sat
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Solver is a conflict-driven clause learning SAT solver in the style of MiniSat: two watched literals, first UIP
+// clause learning, VSIDS branching with phase saving, Luby restarts and activity based removal of learnt clauses.
+// It is meant for the small formulas created by Encode, and makes no attempt at competing with dedicated solvers.
+type Solver struct {
+	ok bool
+
+	clauses  []*clause
+	learnts  []*clause
+	watchers [][]*clause
+
+	assigns  []lbool
+	level    []int
+	reason   []*clause
+	polarity []bool
+	seen     []bool
+
+	trail    []literal
+	trailLim []int
+	qhead    int
+
+	activity   []float64
+	varInc     float64
+	clauseInc  float64
+	order      *variableHeap
+	maxLearnts float64
+	model      []bool
+}
+
+type lbool int8
+
+const (
+	undefined lbool = iota
+	isTrue
+	isFalse
+)
+
+// literal is 2*variable for the positive and 2*variable+1 for the negative literal, with variables numbered from 0.
+type literal int32
+
+func newLiteral(dimacs int) literal {
+	if dimacs < 0 {
+		return literal(2*(-dimacs-1) + 1)
+	}
+	return literal(2 * (dimacs - 1))
+}
+
+func (l literal) not() literal {
+	return l ^ 1
+}
+
+func (l literal) variable() int {
+	return int(l >> 1)
+}
+
+func (l literal) negative() bool {
+	return l&1 == 1
+}
+
+type clause struct {
+	literals []literal
+	learnt   bool
+	activity float64
+}
+
+const (
+	varDecay      = 0.95
+	clauseDecay   = 0.999
+	restartBase   = 100
+	learntsGrowth = 1.1
+)
+
+// NewSolver creates a solver for the given number of variables, numbered from 1 as in DIMACS.
+func NewSolver(variables int) *Solver {
+	s := &Solver{
+		ok:        true,
+		watchers:  make([][]*clause, 2*variables),
+		assigns:   make([]lbool, variables),
+		level:     make([]int, variables),
+		reason:    make([]*clause, variables),
+		polarity:  make([]bool, variables),
+		seen:      make([]bool, variables),
+		activity:  make([]float64, variables),
+		varInc:    1,
+		clauseInc: 1,
+	}
+
+	s.order = &variableHeap{activity: s.activity, indices: make([]int, variables)}
+	for v := 0; v < variables; v++ {
+		s.polarity[v] = true
+		s.order.indices[v] = -1
+		s.order.insert(v)
+	}
+
+	return s
+}
+
+func (s *Solver) value(l literal) lbool {
+	switch s.assigns[l.variable()] {
+	case undefined:
+		return undefined
+	case isTrue:
+		if l.negative() {
+			return isFalse
+		}
+		return isTrue
+	default:
+		if l.negative() {
+			return isTrue
+		}
+		return isFalse
+	}
+}
+
+func (s *Solver) decisionLevel() int {
+	return len(s.trailLim)
+}
+
+// AddClause adds a clause of DIMACS literals, where a terminating 0 is ignored. Returns false when the formula is known
+// to be unsatisfiable.
+//
+// Panics when a literal is 0 before the end of the clause, or its variable exceeds the variables of the solver.
+func (s *Solver) AddClause(dimacs ...int) bool {
+	if len(dimacs) > 0 && dimacs[len(dimacs)-1] == 0 {
+		dimacs = dimacs[:len(dimacs)-1]
+	}
+	for _, d := range dimacs {
+		if d == 0 || d > len(s.assigns) || -d > len(s.assigns) {
+			panic(fmt.Sprintf("literal %d is outside of %d variables", d, len(s.assigns)))
+		}
+	}
+	if !s.ok {
+		return false
+	}
+
+	literals := make([]literal, 0, len(dimacs))
+	for _, d := range dimacs {
+		l := newLiteral(d)
+		switch s.value(l) {
+		case isTrue:
+			return true
+		case isFalse:
+			continue
+		}
+
+		duplicate := false
+		for _, other := range literals {
+			if other == l.not() {
+				return true
+			}
+			duplicate = duplicate || other == l
+		}
+		if !duplicate {
+			literals = append(literals, l)
+		}
+	}
+
+	switch len(literals) {
+	case 0:
+		s.ok = false
+	case 1:
+		s.enqueue(literals[0], nil)
+		s.ok = s.propagate() == nil
+	default:
+		c := &clause{literals: literals}
+		s.clauses = append(s.clauses, c)
+		s.attach(c)
+	}
+
+	return s.ok
+}
+
+func (s *Solver) attach(c *clause) {
+	s.watchers[c.literals[0]] = append(s.watchers[c.literals[0]], c)
+	s.watchers[c.literals[1]] = append(s.watchers[c.literals[1]], c)
+}
+
+func (s *Solver) enqueue(l literal, reason *clause) {
+	v := l.variable()
+	s.assigns[v] = isTrue
+	if l.negative() {
+		s.assigns[v] = isFalse
+	}
+	s.level[v] = s.decisionLevel()
+	s.reason[v] = reason
+	s.trail = append(s.trail, l)
+}
+
+// propagate assigns every literal implied by unit clauses. Returns the conflicting clause, if any.
+func (s *Solver) propagate() *clause {
+	for s.qhead < len(s.trail) {
+		falseLiteral := s.trail[s.qhead].not()
+		s.qhead++
+
+		watchers := s.watchers[falseLiteral]
+		i, j := 0, 0
+		for i < len(watchers) {
+			c := watchers[i]
+			i++
+
+			literals := c.literals
+			if literals[0] == falseLiteral {
+				literals[0], literals[1] = literals[1], literals[0]
+			}
+			if s.value(literals[0]) == isTrue {
+				watchers[j] = c
+				j++
+				continue
+			}
+
+			moved := false
+			for k := 2; k < len(literals); k++ {
+				if s.value(literals[k]) != isFalse {
+					literals[1], literals[k] = literals[k], literals[1]
+					s.watchers[literals[1]] = append(s.watchers[literals[1]], c)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+
+			watchers[j] = c
+			j++
+			if s.value(literals[0]) == isFalse {
+				j += copy(watchers[j:], watchers[i:])
+				s.watchers[falseLiteral] = watchers[:j]
+				s.qhead = len(s.trail)
+				return c
+			}
+			s.enqueue(literals[0], c)
+		}
+		s.watchers[falseLiteral] = watchers[:j]
+	}
+
+	return nil
+}
+
+// analyze derives the first unique implication point clause of the conflict, and the level to backtrack to.
+func (s *Solver) analyze(conflict *clause) ([]literal, int) {
+	learnt := []literal{0}
+	pathCount := 0
+	p := literal(-1)
+	index := len(s.trail) - 1
+
+	for c := conflict; ; {
+		if c.learnt {
+			s.bumpClause(c)
+		}
+
+		start := 0
+		if p != -1 {
+			start = 1
+		}
+		for _, q := range c.literals[start:] {
+			v := q.variable()
+			if s.seen[v] || s.level[v] == 0 {
+				continue
+			}
+
+			s.bumpVariable(v)
+			s.seen[v] = true
+			if s.level[v] >= s.decisionLevel() {
+				pathCount++
+			} else {
+				learnt = append(learnt, q)
+			}
+		}
+
+		for !s.seen[s.trail[index].variable()] {
+			index--
+		}
+		p = s.trail[index]
+		index--
+		c = s.reason[p.variable()]
+		s.seen[p.variable()] = false
+		pathCount--
+
+		if pathCount == 0 {
+			break
+		}
+	}
+	learnt[0] = p.not()
+
+	// remove literals implied by the other literals of the clause
+	minimized := make([]literal, 1, len(learnt))
+	minimized[0] = learnt[0]
+	for _, q := range learnt[1:] {
+		if !s.redundant(q) {
+			minimized = append(minimized, q)
+		}
+	}
+	for _, q := range learnt {
+		s.seen[q.variable()] = false
+	}
+	learnt = minimized
+
+	backtrackLevel := 0
+	for i := 1; i < len(learnt); i++ {
+		if s.level[learnt[i].variable()] > backtrackLevel {
+			backtrackLevel = s.level[learnt[i].variable()]
+			learnt[1], learnt[i] = learnt[i], learnt[1]
+		}
+	}
+
+	return learnt, backtrackLevel
+}
+
+// redundant reports whether every other literal in the reason of q is already part of the learnt clause.
+func (s *Solver) redundant(q literal) bool {
+	reason := s.reason[q.variable()]
+	if reason == nil {
+		return false
+	}
+
+	for _, other := range reason.literals[1:] {
+		v := other.variable()
+		if !s.seen[v] && s.level[v] > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Solver) bumpVariable(v int) {
+	s.activity[v] += s.varInc
+	if s.activity[v] > 1e100 {
+		for i := range s.activity {
+			s.activity[i] *= 1e-100
+		}
+		s.varInc *= 1e-100
+	}
+	s.order.update(v)
+}
+
+func (s *Solver) bumpClause(c *clause) {
+	c.activity += s.clauseInc
+	if c.activity > 1e20 {
+		for _, learnt := range s.learnts {
+			learnt.activity *= 1e-20
+		}
+		s.clauseInc *= 1e-20
+	}
+}
+
+func (s *Solver) cancelUntil(level int) {
+	if s.decisionLevel() <= level {
+		return
+	}
+
+	for i := len(s.trail) - 1; i >= s.trailLim[level]; i-- {
+		v := s.trail[i].variable()
+		s.assigns[v] = undefined
+		s.reason[v] = nil
+		s.polarity[v] = s.trail[i].negative()
+		s.order.insert(v)
+	}
+	s.trail = s.trail[:s.trailLim[level]]
+	s.trailLim = s.trailLim[:level]
+	s.qhead = len(s.trail)
+}
+
+func (s *Solver) pickBranch() (literal, bool) {
+	for !s.order.empty() {
+		v := s.order.removeMax()
+		if s.assigns[v] == undefined {
+			l := literal(2 * v)
+			if s.polarity[v] {
+				l = l.not()
+			}
+			return l, true
+		}
+	}
+
+	return 0, false
+}
+
+func (s *Solver) locked(c *clause) bool {
+	return s.reason[c.literals[0].variable()] == c && s.value(c.literals[0]) == isTrue
+}
+
+// reduceLearnts removes half of the learnt clauses, preferring those least involved in recent conflicts.
+func (s *Solver) reduceLearnts() {
+	sort.Slice(s.learnts, func(i, j int) bool {
+		return s.learnts[i].activity < s.learnts[j].activity
+	})
+
+	removed := map[*clause]bool{}
+	kept := s.learnts[:0]
+	for i, c := range s.learnts {
+		if i < len(s.learnts)/2 && len(c.literals) > 2 && !s.locked(c) {
+			removed[c] = true
+			continue
+		}
+		kept = append(kept, c)
+	}
+	s.learnts = kept
+
+	for l, watchers := range s.watchers {
+		remaining := watchers[:0]
+		for _, c := range watchers {
+			if !removed[c] {
+				remaining = append(remaining, c)
+			}
+		}
+		s.watchers[l] = remaining
+	}
+}
+
+// search runs until a model is found, the formula is proven unsatisfiable or the conflict limit is reached.
+func (s *Solver) search(conflictLimit int) lbool {
+	for conflicts := 0; ; {
+		if conflict := s.propagate(); conflict != nil {
+			conflicts++
+			if s.decisionLevel() == 0 {
+				return isFalse
+			}
+
+			learnt, backtrackLevel := s.analyze(conflict)
+			s.cancelUntil(backtrackLevel)
+			if len(learnt) == 1 {
+				s.enqueue(learnt[0], nil)
+			} else {
+				c := &clause{literals: learnt, learnt: true}
+				s.learnts = append(s.learnts, c)
+				s.attach(c)
+				s.bumpClause(c)
+				s.enqueue(learnt[0], c)
+			}
+
+			s.varInc /= varDecay
+			s.clauseInc /= clauseDecay
+			continue
+		}
+
+		if conflicts >= conflictLimit {
+			s.cancelUntil(0)
+			return undefined
+		}
+		if float64(len(s.learnts)-len(s.trail)) >= s.maxLearnts {
+			s.reduceLearnts()
+			s.maxLearnts *= learntsGrowth
+		}
+
+		l, ok := s.pickBranch()
+		if !ok {
+			return isTrue
+		}
+		s.trailLim = append(s.trailLim, len(s.trail))
+		s.enqueue(l, nil)
+	}
+}
+
+// Solve reports whether the clauses are satisfiable. The search can be interrupted between restarts through the
+// context, in which case the context error is returned.
+func (s *Solver) Solve(ctx context.Context) (bool, error) {
+	if !s.ok {
+		return false, nil
+	}
+
+	s.maxLearnts = float64(len(s.clauses))/3 + 1000
+	for restart := 1; ; restart++ {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		switch s.search(restartBase * luby(restart)) {
+		case isTrue:
+			s.model = make([]bool, len(s.assigns)+1)
+			for v, value := range s.assigns {
+				s.model[v+1] = value == isTrue
+			}
+			s.cancelUntil(0)
+			return true, nil
+		case isFalse:
+			s.ok = false
+			return false, nil
+		}
+	}
+}
+
+// Model returns the satisfying assignment found by the last call to Solve, indexed by DIMACS variable. Index 0 is
+// unused.
+func (s *Solver) Model() []bool {
+	return s.model
+}
+
+// luby returns the i'th element of the Luby sequence 1, 1, 2, 1, 1, 2, 4, 1, 1, 2, ... counting from 1.
+func luby(i int) int {
+	for k := 1; ; k++ {
+		if i == (1<<k)-1 {
+			return 1 << (k - 1)
+		}
+		if i < (1<<k)-1 {
+			return luby(i - (1 << (k - 1)) + 1)
+		}
+	}
+}
+
+// variableHeap is a max-heap of variables ordered by activity.
+type variableHeap struct {
+	activity []float64
+	heap     []int
+	indices  []int
+}
+
+func (h *variableHeap) empty() bool {
+	return len(h.heap) == 0
+}
+
+func (h *variableHeap) less(i, j int) bool {
+	return h.activity[h.heap[i]] > h.activity[h.heap[j]]
+}
+
+func (h *variableHeap) swap(i, j int) {
+	h.heap[i], h.heap[j] = h.heap[j], h.heap[i]
+	h.indices[h.heap[i]] = i
+	h.indices[h.heap[j]] = j
+}
+
+func (h *variableHeap) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(i, parent) {
+			return
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+func (h *variableHeap) down(i int) {
+	for {
+		child := 2*i + 1
+		if child >= len(h.heap) {
+			return
+		}
+		if child+1 < len(h.heap) && h.less(child+1, child) {
+			child++
+		}
+		if !h.less(child, i) {
+			return
+		}
+		h.swap(i, child)
+		i = child
+	}
+}
+
+func (h *variableHeap) insert(v int) {
+	if h.indices[v] >= 0 {
+		return
+	}
+	h.indices[v] = len(h.heap)
+	h.heap = append(h.heap, v)
+	h.up(len(h.heap) - 1)
+}
+
+func (h *variableHeap) update(v int) {
+	if h.indices[v] >= 0 {
+		h.up(h.indices[v])
+	}
+}
+
+func (h *variableHeap) removeMax() int {
+	v := h.heap[0]
+	last := len(h.heap) - 1
+	h.swap(0, last)
+	h.heap = h.heap[:last]
+	h.indices[v] = -1
+	if last > 0 {
+		h.down(0)
+	}
+	return v
+}
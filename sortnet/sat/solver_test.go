@@ -0,0 +1,101 @@
+package sat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+func TestSolver(t *testing.T) {
+	// 4 pigeons do not fit in 3 holes, variable 3*p+h+1 places pigeon p in hole h
+	pigeons := NewSolver(12)
+	for p := 0; p < 4; p++ {
+		pigeons.AddClause(3*p+1, 3*p+2, 3*p+3)
+		for q := p + 1; q < 4; q++ {
+			for h := 1; h <= 3; h++ {
+				pigeons.AddClause(-(3*p + h), -(3*q + h))
+			}
+		}
+	}
+	if ok, err := pigeons.Solve(context.Background()); ok || err != nil {
+		t.Errorf("expected the pigeonhole formula to be unsatisfiable, got %t %v", ok, err)
+	}
+
+	s := NewSolver(3)
+	s.AddClause(1, 2)
+	s.AddClause(-1, 3)
+	s.AddClause(-3, -2)
+	s.AddClause(-2)
+	if ok, _ := s.Solve(context.Background()); !ok {
+		t.Fatal("expected the formula to be satisfiable")
+	}
+	if model := s.Model(); !model[1] || model[2] || !model[3] {
+		t.Errorf("unexpected model %v", model)
+	}
+
+	terminated := NewSolver(2)
+	terminated.AddClause(1, 0)
+	terminated.AddClause(-1, 2, 0)
+	if ok, _ := terminated.Solve(context.Background()); !ok || !terminated.Model()[2] {
+		t.Error("expected the terminating 0 of a clause to be ignored")
+	}
+	for _, clause := range [][]int{{0, 1}, {3}, {-3}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for the clause %v of 2 variables", clause)
+				}
+			}()
+			NewSolver(2).AddClause(clause...)
+		}()
+	}
+}
+
+func TestSolve(t *testing.T) {
+	firstLayer, _ := sortnet.ParseComparatorNetwork("[(0,1),(2,3),(4,5)]")
+
+	problems := []struct {
+		problem     Problem
+		satisfiable bool
+	}{
+		{Problem{Channels: 4, Comparators: 5}, true},
+		{Problem{Channels: 4, Comparators: 4}, false},
+		{Problem{Channels: 4, Depth: 3}, true},
+		{Problem{Channels: 4, Depth: 2}, false},
+		{Problem{Channels: 5, Depth: 5}, true},
+		{Problem{Channels: 5, Depth: 4}, false},
+		{Problem{Channels: 6, Depth: 4, Prefix: firstLayer}, true},
+		{Problem{Channels: 6, Depth: 3, Prefix: firstLayer}, false},
+		{Problem{Channels: 1, Comparators: 1}, true},
+		{Problem{Channels: 1, Depth: 2}, true},
+	}
+
+	for _, p := range problems {
+		f, err := Encode(p.problem)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		network, err := f.Solve(context.Background())
+		if !p.satisfiable {
+			if err != ErrUnsatisfiable {
+				t.Errorf("%+v: expected ErrUnsatisfiable, got %v", p.problem, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%+v: %s", p.problem, err)
+		}
+
+		if ok, counterexample := sortnet.IsSortingNetwork(network, p.problem.Channels); !ok {
+			t.Errorf("%+v: not sorting, counterexample %+v", p.problem, counterexample)
+		}
+		if p.problem.Channels == 1 && network.Len() != 0 {
+			t.Errorf("%+v: expected the empty network, got %s", p.problem, network)
+		}
+		if p.problem.Depth > 0 && p.problem.Prefix == nil && network.Depth() > p.problem.Depth {
+			t.Errorf("%+v: expected at most depth %d, got %d", p.problem, p.problem.Depth, network.Depth())
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package search
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+// checkpointVersion is increased whenever the checkpoint layout changes, as older checkpoints can then no longer be
+// resumed.
+const checkpointVersion = 5
+
+// checkpoint is the file layout of a search state. Networks are stored as nodes of the prefix tree of the store, and
+// output sets in their binary encoding, where a pruned network is marked in Pruned. IDs holds the certificate id of
+// every network, and Certificate is set when a certificate was written, which then ends at CertificateOffset. The
+// output set implementation and the permutation generator are identified by their names, see settings.
+type checkpoint struct {
+	Version    int
+	Channels   int
+	Mode       Mode
+	AllLayers  bool
+	Reflection bool
+	Width      int
+	Set        string
+	Generator  string
+
+	Round     int
+	Pruning   bool
+	PruneFrom int
+	Stats     RoundStats
+	Rounds    []RoundStats
+
+	Nodes    []checkpointNode
+	Networks []int
+	Sets     [][]byte
+	Pruned   []bool
+	IDs      []int
+
+	Certificate       bool
+	CertificateOffset int64
+}
+
+// settings names the output set implementation and the permutation generator of the configuration, which decide how
+// the sets of a checkpoint decode and which of them are pruned.
+func (e *Engine) settings() (set, generator string) {
+	set = fmt.Sprintf("%T", e.config.NewSet(1))
+	generator = runtime.FuncForPC(reflect.ValueOf(e.config.GeneratePermutations).Pointer()).Name()
+	return set, generator
+}
+
+// checkpointNode is a network of the prefix tree as its parent and the comparator appended to it. Node 0 is the empty
+// network, and parents precede their children.
+type checkpointNode struct {
+	Parent     int
+	Comparator sortnet.Comparator
+}
+
+// checkpointTree returns the prefix tree of the networks, and the node of every network.
+func checkpointTree(store *sortnet.NetworkStore, networks []sortnet.NetworkID) ([]checkpointNode, []int) {
+	nodes := []checkpointNode{{}}
+	indices := map[sortnet.NetworkID]int{sortnet.RootNetwork: 0}
+	leaves := make([]int, len(networks))
+	for i, id := range networks {
+		lineage := store.Lineage(id)
+		for j := 1; j < len(lineage); j++ {
+			if _, ok := indices[lineage[j]]; ok {
+				continue
+			}
+			comparator, _ := store.Last(lineage[j])
+			indices[lineage[j]] = len(nodes)
+			nodes = append(nodes, checkpointNode{Parent: indices[lineage[j-1]], Comparator: comparator})
+		}
+		leaves[i] = indices[id]
+	}
+
+	return nodes, leaves
+}
+
+// saveCheckpoint writes the state to the checkpoint path. The file is replaced atomically, so a crash while writing
+// leaves the previous checkpoint intact.
+func (e *Engine) saveCheckpoint(s *state, result *Result) error {
+	if e.config.CheckpointPath == "" {
+		return nil
+	}
+
+	set, generator := e.settings()
+	cp := checkpoint{
+		Version:    checkpointVersion,
+		Channels:   e.config.Channels,
+		Mode:       e.config.Mode,
+		AllLayers:  e.config.AllLayers,
+		Reflection: e.config.Reflection,
+		Width:      sortnet.MaxChannels,
+		Set:        set,
+		Generator:  generator,
+		Round:      s.round,
+		Pruning:    s.pruning,
+		PruneFrom:  s.pruneFrom,
+		Stats:      s.stats,
+		Rounds:     result.Rounds,
+		Sets:       make([][]byte, len(s.sets)),
+		Pruned:     make([]bool, len(s.sets)),
+		IDs:        s.ids,
+	}
+	if s.cert != nil {
+		// the records of the pruned sets must be written before the checkpoint refers to them
+		if err := s.cert.Flush(); err != nil {
+			return fmt.Errorf("writing certificate: %w", err)
+		}
+		cp.Certificate, cp.CertificateOffset = true, s.written.n
+	}
+	cp.Nodes, cp.Networks = checkpointTree(s.store, s.networks)
+	for i := range s.networks {
+		if s.sets[i] == nil {
+			cp.Pruned[i] = true
+			continue
+		}
+
+		var err error
+		if cp.Sets[i], err = s.sets[i].MarshalBinary(); err != nil {
+			return fmt.Errorf("encoding output set %d: %w", i, err)
+		}
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(e.config.CheckpointPath), filepath.Base(e.config.CheckpointPath)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	w := bufio.NewWriter(file)
+	if err = gob.NewEncoder(w).Encode(&cp); err == nil {
+		err = w.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+
+	return os.Rename(file.Name(), e.config.CheckpointPath)
+}
+
+// loadCheckpoint restores the state and the finished rounds from the checkpoint path.
+func (e *Engine) loadCheckpoint(s *state, result *Result) error {
+	file, err := os.Open(e.config.CheckpointPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var cp checkpoint
+	if err = gob.NewDecoder(bufio.NewReader(file)).Decode(&cp); err != nil {
+		return fmt.Errorf("reading checkpoint: %w", err)
+	}
+
+	set, generator := e.settings()
+	switch {
+	case cp.Version != checkpointVersion:
+		return fmt.Errorf("checkpoint version %d is not supported, expected %d", cp.Version, checkpointVersion)
+	case cp.Width != sortnet.MaxChannels:
+		return fmt.Errorf("checkpoint was written for binary sequences of %d channels, got %d", cp.Width, sortnet.MaxChannels)
+	case cp.Channels != e.config.Channels || cp.Mode != e.config.Mode || cp.AllLayers != e.config.AllLayers:
+		return fmt.Errorf("checkpoint was written for %d channels in mode %d, which does not match the configuration", cp.Channels, cp.Mode)
+	case cp.Reflection != e.config.Reflection:
+		return fmt.Errorf("checkpoint was written with reflection %t, which does not match the configuration", cp.Reflection)
+	case cp.Set != set || cp.Generator != generator:
+		return fmt.Errorf("checkpoint was written with output set %s and permutation generator %s, which do not match the configuration", cp.Set, cp.Generator)
+	case len(cp.Sets) != len(cp.Networks) || len(cp.Pruned) != len(cp.Networks) || len(cp.IDs) != len(cp.Networks):
+		return fmt.Errorf("checkpoint holds %d networks but %d output sets", len(cp.Networks), len(cp.Sets))
+	case e.config.Certificate != nil && !cp.Certificate:
+		return errors.New("checkpoint was written without a certificate")
+	}
+
+	// rebuild the prefix tree, where every node is held until the networks hold their own
+	nodes := make([]sortnet.NetworkID, len(cp.Nodes))
+	for i := 1; i < len(cp.Nodes); i++ {
+		node := cp.Nodes[i]
+		if node.Parent < 0 || node.Parent >= i {
+			return fmt.Errorf("checkpoint node %d has the invalid parent %d", i, node.Parent)
+		}
+		if _, err := sortnet.NewComparatorNetwork(cp.Channels, node.Comparator); err != nil {
+			return fmt.Errorf("checkpoint node %d: %w", i, err)
+		}
+		nodes[i] = s.store.Add(nodes[node.Parent], node.Comparator)
+	}
+	defer func() {
+		for _, id := range nodes[1:] {
+			s.store.Release(id)
+		}
+	}()
+
+	empty := e.config.NewSet(e.config.Channels)
+	s.networks = make([]sortnet.NetworkID, len(cp.Networks))
+	s.sets = make([]sortnet.OutputSet, len(cp.Sets))
+	for i, node := range cp.Networks {
+		if node < 0 || node >= len(nodes) {
+			return fmt.Errorf("checkpoint network %d refers to the missing node %d", i, node)
+		}
+		s.networks[i] = s.store.Add(nodes[node])
+
+		if cp.Pruned[i] {
+			continue
+		}
+		set := empty.NewEmpty()
+		if err := set.UnmarshalBinary(cp.Sets[i]); err != nil {
+			return fmt.Errorf("checkpoint output set %d: %w", i, err)
+		}
+		s.sets[i] = set
+	}
+
+	s.ids = cp.IDs
+	s.round, s.pruning, s.pruneFrom, s.stats = cp.Round, cp.Pruning, cp.PruneFrom, cp.Stats
+	result.Rounds = cp.Rounds
+	return e.resumeCertificate(s, cp.CertificateOffset)
+}
@@ -0,0 +1,123 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/outputset"
+)
+
+// countdownContext is cancelled after Err has been called the given number of times, which interrupts a search at a
+// deterministic point.
+type countdownContext struct {
+	context.Context
+	calls int
+}
+
+func (c *countdownContext) Err() error {
+	if c.calls--; c.calls < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func summary(result *Result) string {
+	rounds := make([]RoundStats, len(result.Rounds))
+	for i, stats := range result.Rounds {
+		stats.Duration = 0
+		rounds[i] = stats
+	}
+	return fmt.Sprint(result.Comparators, result.Networks, rounds)
+}
+
+func TestResume(t *testing.T) {
+	config := Config{Channels: 5, Workers: 2}
+	engine, _ := New(config)
+	expected, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, interrupt := range []int{1, 3, 20, 40, 70} {
+		config.CheckpointPath = filepath.Join(t.TempDir(), "checkpoint")
+		config.CheckpointEvery = 7
+		config.Resume = false
+		engine, _ = New(config)
+		if _, err = engine.Run(&countdownContext{Context: context.Background(), calls: interrupt}); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the search to be cancelled after %d checks, got %v", interrupt, err)
+		}
+
+		// resume twice, as a checkpoint written by a resumed search must be resumable as well
+		config.Resume = true
+		engine, _ = New(config)
+		if _, err = engine.Run(&countdownContext{Context: context.Background(), calls: interrupt}); err != nil && !errors.Is(err, context.Canceled) {
+			t.Fatal(err)
+		}
+		result, err := engine.Run(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if summary(result) != summary(expected) {
+			t.Errorf("interrupted after %d checks: expected %s, got %s", interrupt, summary(expected), summary(result))
+		}
+	}
+
+	mismatched := map[string]Config{
+		"channel count":         {Channels: 4},
+		"reflection":            {Channels: 5, Reflection: true},
+		"output set":            {Channels: 5, NewSet: outputset.NewDense},
+		"permutation generator": {Channels: 5, GeneratePermutations: sortnet.SubsetPermutationGenerators["bitmap"]},
+	}
+	for name, changed := range mismatched {
+		changed.Workers, changed.CheckpointPath, changed.Resume = 2, config.CheckpointPath, true
+		engine, _ = New(changed)
+		if _, err = engine.Run(context.Background()); err == nil {
+			t.Errorf("expected a checkpoint of another %s to be rejected", name)
+		}
+	}
+}
+
+func TestResumeSharesPrefixes(t *testing.T) {
+	engine, _ := New(Config{Channels: 5, Workers: 2, CheckpointPath: filepath.Join(t.TempDir(), "checkpoint")})
+	s := &state{store: sortnet.NewNetworkStore(5), networks: []sortnet.NetworkID{sortnet.RootNetwork}}
+	s.sets = []sortnet.OutputSet{engine.config.NewSet(5)}
+	for round := 1; round <= 3; round++ {
+		children, err := engine.generate(context.Background(), round, s.store, s.networks, s.sets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range s.networks {
+			s.store.Release(id)
+		}
+		s.round, s.networks, s.sets, s.ids = round, children.networks, children.sets, children.ids
+	}
+	if err := engine.saveCheckpoint(s, &Result{}); err != nil {
+		t.Fatal(err)
+	}
+
+	resumed := &state{store: sortnet.NewNetworkStore(5)}
+	if err := engine.loadCheckpoint(resumed, &Result{}); err != nil {
+		t.Fatal(err)
+	}
+	if resumed.store.Len() != s.store.Len() {
+		t.Errorf("expected the resumed store to hold %d networks, got %d", s.store.Len(), resumed.store.Len())
+	}
+	for i := range s.networks {
+		if fmt.Sprint(resumed.store.Comparators(resumed.networks[i])) != fmt.Sprint(s.store.Comparators(s.networks[i])) {
+			t.Fatalf("network %d differs after resuming", i)
+		}
+	}
+
+	// releasing every network empties the store, as no node is held twice
+	for _, id := range resumed.networks {
+		resumed.store.Release(id)
+	}
+	if resumed.store.Len() != 1 {
+		t.Errorf("expected only the root network to remain, got %d networks", resumed.store.Len())
+	}
+}
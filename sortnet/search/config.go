@@ -0,0 +1,147 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/outputset"
+)
+
+type PruningStrategy int
+
+const (
+	ParallelPruning PruningStrategy = iota
+	SerialPruning
+)
+
+// Mode decides what a round adds to every network, and therefore what the search minimises.
+type Mode int
+
+const (
+	// SizeMode appends a single comparator per round, finding networks with the fewest comparators.
+	SizeMode Mode = iota
+
+	// DepthMode appends a whole layer per round, finding networks with the fewest layers, following Bundala and
+	// Závodný. The first layer is fixed to the comparators (0,1),(2,3),..., as every sorting network can be
+	// rearranged to start with it.
+	DepthMode
+)
+
+// Pruner runs the subsumption tests of a round outside of the engine, eg. spread over several processes as done by
+// package distributed.
+type Pruner interface {
+	// Round starts pruning the output sets of a new round, where pruned networks have a nil set. The subsumption
+	// tests must also try the reflection of the subsuming set when reflection is set, as in Config.Reflection.
+	Round(ctx context.Context, channels int, reflection bool, sets []sortnet.OutputSet) error
+
+	// Subsumed returns the sets subsumed by the set with the given id, among those not pruned yet, with the witness
+	// of every subsumption. The returned sets are considered pruned from then on, and must be sorted by id.
+	Subsumed(ctx context.Context, id int) ([]Subsumption, error)
+}
+
+// Config holds the settings of a search. Zero values are replaced by the defaults documented on each field.
+type Config struct {
+	// Channels also known as "N", sets the number of network channels or the sequence length.
+	Channels int
+
+	// Mode decides whether the search minimises the size or the depth. Defaults to SizeMode.
+	Mode Mode
+
+	// AllLayers makes DepthMode derive every non-empty layer, instead of only the maximal layers where every channel
+	// but at most one is used. Maximal layers are enough to reach the optimal depth, and keep each round much smaller.
+	AllLayers bool
+
+	// NewSet creates the output set of the empty network. Defaults to outputset.NewPartitionedOrdered.
+	NewSet outputset.NewSet
+
+	// GeneratePermutations is used for subsumption tests. Defaults to sortnet.GeneratePermutationsBySubset, and
+	// sortnet.TestedPermutations adapts generators that only use the metadata of the sets.
+	GeneratePermutations sortnet.GenerateSubsetPermutationsFunc
+
+	// PruningStrategy decides whether subsumption tests of a round run in parallel. Defaults to ParallelPruning.
+	PruningStrategy PruningStrategy
+
+	// Reflection also tries the reflection of every output set in the subsumption tests, that is the output set of the
+	// network with the channels in reverse order, see sortnet.ReflectOutputSet. More networks are pruned per round, at
+	// the cost of a second set of tests. Passed on to the Pruner when one is set.
+	Reflection bool
+
+	// Pruner replaces the PruningStrategy with subsumption tests run elsewhere. Optional.
+	Pruner Pruner
+
+	// Workers is the number of goroutines used for generating and pruning. Defaults to runtime.NumCPU().
+	Workers int
+
+	// MaxRounds stops the search once the given number of comparators, or layers in DepthMode, has been tried. 0
+	// means no limit.
+	MaxRounds int
+
+	// OnRound is called after every round, eg. for reporting progress. Optional.
+	OnRound func(RoundStats)
+
+	// Certificate receives a proof certificate of the search, which records the witness of every pruned network, see
+	// package cert. Optional. When resuming, it must be the certificate the checkpoint was written with, and implement
+	// CertificateFile as *os.File does: the records written after the checkpoint are truncated and written again.
+	Certificate io.Writer
+
+	// CheckpointPath is the file the state of the search is written to after every round, and when the search is
+	// cancelled while pruning. Optional.
+	CheckpointPath string
+
+	// CheckpointEvery also writes the checkpoint while pruning, every time the given number of networks have been
+	// tested for subsuming the others. 0 only writes the checkpoint after every round.
+	CheckpointEvery int
+
+	// Resume continues the search from the checkpoint at CheckpointPath. The checkpoint must have been written with
+	// the same channels, mode, layers, reflection, output set and permutation generator, and the search then gives
+	// the same result as an uninterrupted one.
+	Resume bool
+}
+
+// CertificateFile is a certificate that can be continued when resuming a search.
+type CertificateFile interface {
+	io.WriteSeeker
+	Truncate(size int64) error
+}
+
+func (c *Config) setDefaults() {
+	if c.NewSet == nil {
+		c.NewSet = outputset.NewPartitionedOrdered
+	}
+	if c.GeneratePermutations == nil {
+		c.GeneratePermutations = sortnet.GeneratePermutationsBySubset
+	}
+	if c.Workers <= 0 {
+		c.Workers = runtime.NumCPU()
+	}
+}
+
+func (c *Config) validate() error {
+	if c.Channels < 1 || c.Channels > sortnet.MaxChannels {
+		return fmt.Errorf("channels must be in the range [1, %d], got %d", sortnet.MaxChannels, c.Channels)
+	}
+	if c.Mode != SizeMode && c.Mode != DepthMode {
+		return errors.New("unknown search mode")
+	}
+	if c.PruningStrategy != ParallelPruning && c.PruningStrategy != SerialPruning {
+		return errors.New("unknown pruning strategy")
+	}
+	if c.MaxRounds < 0 {
+		return errors.New("max rounds can not be negative")
+	}
+	if c.CheckpointEvery < 0 {
+		return errors.New("checkpoint interval can not be negative")
+	}
+	if c.Resume && c.CheckpointPath == "" {
+		return errors.New("resuming requires a checkpoint path")
+	}
+	if _, ok := c.Certificate.(CertificateFile); c.Certificate != nil && c.Resume && !ok {
+		return errors.New("resuming a certificate requires it to be seekable and truncatable")
+	}
+
+	return nil
+}
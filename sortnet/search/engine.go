@@ -0,0 +1,357 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/cert"
+	"golang.org/x/sync/errgroup"
+)
+
+var ErrRoundLimit = errors.New("round limit reached before a sorting network was discovered")
+
+// Engine implements the generate-and-prune approach by Codish et al. Each round derives every network with one
+// more comparator, computes the output sets and removes the networks whose output sets are subsumed by another.
+// The search ends in the first round that produces a sorting network, which is then of minimal size. In DepthMode a
+// round adds a layer instead of a comparator, and the discovered networks are of minimal depth.
+type Engine struct {
+	config     Config
+	steps      [][]sortnet.Comparator
+	layers     [][]sortnet.Comparator
+	firstLayer [][]sortnet.Comparator
+}
+
+func New(config Config) (*Engine, error) {
+	config.setDefaults()
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	e := &Engine{config: config}
+	for _, comparator := range sortnet.AllComparatorCombinations(config.Channels) {
+		e.steps = append(e.steps, []sortnet.Comparator{comparator})
+	}
+	if config.Mode == DepthMode {
+		e.layers = sortnet.Matchings(config.Channels, !config.AllLayers)
+
+		var first []sortnet.Comparator
+		for channel := 0; channel+1 < config.Channels; channel += 2 {
+			first = append(first, sortnet.Comparator{From: channel + 1, To: channel})
+		}
+		e.firstLayer = [][]sortnet.Comparator{first}
+	}
+
+	return e, nil
+}
+
+// state is the progress of a search, as kept in a checkpoint.
+type state struct {
+	// round is the last round that was generated.
+	round int
+	sets  []sortnet.OutputSet
+
+	// networks holds the ID of the network of every output set, which is held in the store until pruned.
+	store    *sortnet.NetworkStore
+	networks []sortnet.NetworkID
+
+	// pruning is set while the networks of the round are pruned, and pruneFrom is the next network to prune with.
+	pruning   bool
+	pruneFrom int
+	stats     RoundStats
+
+	// cert writes the certificate when configured, where ids holds the certificate id of every set of the round and
+	// written counts the bytes of the certificate.
+	cert    *cert.Writer
+	written *countingWriter
+	ids     []int
+}
+
+func (e *Engine) Run(ctx context.Context) (*Result, error) {
+	s := &state{store: sortnet.NewNetworkStore(e.config.Channels)}
+	result := &Result{}
+	if e.config.Resume {
+		if err := e.loadCheckpoint(s, result); err != nil {
+			return nil, err
+		}
+	} else {
+		s.networks = []sortnet.NetworkID{sortnet.RootNetwork}
+		s.sets = []sortnet.OutputSet{e.config.NewSet(e.config.Channels)}
+		s.ids = []int{0}
+		if err := e.startCertificate(s); err != nil {
+			return nil, err
+		}
+		if e.sorted(s.sets[0]) {
+			result.Networks = []sortnet.Network{s.store.Network(sortnet.RootNetwork)}
+			return result, e.endCertificateRound(s, e.sorted)
+		}
+	}
+
+	for {
+		start := time.Now()
+		elapsed := s.stats.Duration
+
+		if !s.pruning {
+			round := s.round + 1
+			if e.config.MaxRounds > 0 && round > e.config.MaxRounds {
+				return result, ErrRoundLimit
+			}
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+
+			s.stats = RoundStats{Round: round}
+			children, err := e.generate(ctx, round, s.store, s.networks, s.sets)
+			if err != nil {
+				return result, err
+			}
+			s.stats.Redundant = children.redundant
+			s.stats.Generated = len(children.networks) + children.redundant
+
+			// the children hold their parents from now on
+			for _, id := range s.networks {
+				s.store.Release(id)
+			}
+			s.round, s.networks, s.sets, s.ids = round, children.networks, children.sets, children.ids
+
+			if sorting := e.sortingNetworks(s.store, s.networks, s.sets); len(sorting) > 0 {
+				s.stats.Remaining = len(sorting)
+				s.stats.Duration = time.Since(start)
+				e.addRound(result, s.stats)
+				result.Networks = sorting
+				if e.config.Mode == DepthMode {
+					result.Depth = round
+				} else {
+					result.Comparators = round
+				}
+				return result, e.endCertificateRound(s, e.sorted)
+			}
+
+			s.pruning, s.pruneFrom = true, 0
+		}
+
+		err := e.prune(ctx, s, func() error {
+			s.stats.Duration = elapsed + time.Since(start)
+			return e.saveCheckpoint(s, result)
+		})
+		if err != nil {
+			return result, err
+		}
+		s.networks, s.sets, s.ids = survivors(s.store, s.networks, s.sets, s.ids)
+		s.pruning = false
+		if err = e.endCertificateRound(s, nil); err != nil {
+			return result, err
+		}
+
+		s.stats.Remaining = len(s.networks)
+		s.stats.Duration = elapsed + time.Since(start)
+		e.addRound(result, s.stats)
+		s.stats = RoundStats{}
+
+		if err = e.saveCheckpoint(s, result); err != nil {
+			return result, err
+		}
+	}
+}
+
+func (e *Engine) startCertificate(s *state) error {
+	if e.config.Certificate == nil {
+		return nil
+	}
+
+	mode := cert.SizeMode
+	if e.config.Mode == DepthMode {
+		mode = cert.DepthMode
+	}
+
+	s.written = &countingWriter{w: e.config.Certificate}
+	s.cert = cert.NewWriter(s.written)
+	if err := s.cert.Header(cert.Header{Channels: e.config.Channels, Mode: mode, AllLayers: e.config.AllLayers}); err != nil {
+		return fmt.Errorf("writing certificate: %w", err)
+	}
+	return nil
+}
+
+// resumeCertificate continues the certificate from the given offset, dropping what was written after the checkpoint.
+func (e *Engine) resumeCertificate(s *state, offset int64) error {
+	if e.config.Certificate == nil {
+		return nil
+	}
+
+	file := e.config.Certificate.(CertificateFile)
+	if err := file.Truncate(offset); err != nil {
+		return fmt.Errorf("resuming certificate: %w", err)
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("resuming certificate: %w", err)
+	}
+
+	s.written = &countingWriter{w: file, n: offset}
+	s.cert = cert.NewWriter(s.written)
+	return nil
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// endCertificateRound writes the end of the round to the certificate. In the last round sorting is set, and the sets
+// it holds for are listed as sorting networks. Otherwise every set of the state survived the round.
+func (e *Engine) endCertificateRound(s *state, sorting func(sortnet.OutputSet) bool) error {
+	if s.cert == nil {
+		return nil
+	}
+
+	round := cert.Round{Round: s.round}
+	for i, id := range s.ids {
+		switch {
+		case sorting == nil:
+			round.Survivors = append(round.Survivors, id)
+		case sorting(s.sets[i]):
+			round.Sorting = append(round.Sorting, id)
+		}
+	}
+
+	if err := s.cert.Round(round); err != nil {
+		return fmt.Errorf("writing certificate: %w", err)
+	}
+	return nil
+}
+
+func (e *Engine) addRound(result *Result, stats RoundStats) {
+	result.Rounds = append(result.Rounds, stats)
+	if e.config.OnRound != nil {
+		e.config.OnRound(stats)
+	}
+}
+
+// sorted reports whether the output set only holds sorted sequences. Every output set contains the channels-1 sorted
+// sequences, as a comparator network never changes a sorted input.
+func (e *Engine) sorted(set sortnet.OutputSet) bool {
+	return set.Size() <= e.config.Channels-1
+}
+
+func (e *Engine) sortingNetworks(store *sortnet.NetworkStore, networks []sortnet.NetworkID, sets []sortnet.OutputSet) []sortnet.Network {
+	var sorting []sortnet.Network
+	for i := range sets {
+		if e.sorted(sets[i]) {
+			sorting = append(sorting, store.Network(networks[i]))
+		}
+	}
+
+	return sorting
+}
+
+// extensions returns the comparators a round appends to a network, one slice per child.
+func (e *Engine) extensions(round int) [][]sortnet.Comparator {
+	switch {
+	case e.config.Mode == SizeMode:
+		return e.steps
+	case round == 1:
+		return e.firstLayer
+	default:
+		return e.layers
+	}
+}
+
+// generation holds the children of a round, where ids numbers every child by its parent and extension as done in a
+// certificate. Redundant children are only counted.
+type generation struct {
+	networks  []sortnet.NetworkID
+	sets      []sortnet.OutputSet
+	ids       []int
+	redundant int
+}
+
+// generate derives the children of every network and their output sets. The output set of a child is derived from the
+// output set of its parent by only the comparators the child added. Children whose last comparator or layer did not
+// change the output set of the parent are redundant and dropped, and the others are added to the store.
+func (e *Engine) generate(ctx context.Context, round int, store *sortnet.NetworkStore, networks []sortnet.NetworkID, sets []sortnet.OutputSet) (*generation, error) {
+	extensions := e.extensions(round)
+	families := make([]generation, len(networks))
+
+	g, ctx := errgroup.WithContext(ctx)
+	work := make(chan int)
+	g.Go(func() error {
+		defer close(work)
+		for i := range networks {
+			select {
+			case work <- i:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+
+	for w := 0; w < e.config.Workers; w++ {
+		g.Go(func() error {
+			for i := range work {
+				f := &families[i]
+				last, hasLast := store.Last(networks[i])
+				for j, added := range extensions {
+					// repeating the last comparator never changes the output set
+					if e.config.Mode == SizeMode && hasLast && added[0] == last {
+						continue
+					}
+
+					childSet := sortnet.DeriveFrom(sets[i], added...)
+					if sets[i].Size() == childSet.Size() && sets[i].IsSubset(childSet, nil) {
+						f.redundant++
+						continue
+					}
+
+					f.networks = append(f.networks, store.Add(networks[i], added...))
+					f.ids = append(f.ids, i*len(extensions)+j)
+					f.sets = append(f.sets, childSet)
+				}
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	children := &generation{}
+	for _, f := range families {
+		children.networks = append(children.networks, f.networks...)
+		children.sets = append(children.sets, f.sets...)
+		children.ids = append(children.ids, f.ids...)
+		children.redundant += f.redundant
+	}
+
+	return children, nil
+}
+
+// survivors returns the networks that were not pruned with their sets and ids, and releases the pruned networks from
+// the store.
+func survivors(store *sortnet.NetworkStore, networks []sortnet.NetworkID, sets []sortnet.OutputSet, ids []int) ([]sortnet.NetworkID, []sortnet.OutputSet, []int) {
+	remainingNetworks := make([]sortnet.NetworkID, 0, len(networks))
+	remainingSets := make([]sortnet.OutputSet, 0, len(sets))
+	remainingIDs := make([]int, 0, len(ids))
+	for i := range sets {
+		if sets[i] == nil {
+			store.Release(networks[i])
+			continue
+		}
+
+		remainingNetworks = append(remainingNetworks, networks[i])
+		remainingSets = append(remainingSets, sets[i])
+		remainingIDs = append(remainingIDs, ids[i])
+	}
+
+	return remainingNetworks, remainingSets, remainingIDs
+}
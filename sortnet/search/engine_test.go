@@ -0,0 +1,262 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/cert"
+	"github.com/andersfylling/go-sortnet/sortnet/outputset"
+	"github.com/andersfylling/go-sortnet/sortnet/spill"
+)
+
+func TestEngine(t *testing.T) {
+	optimalSizes := []int{0, 0, 1, 3, 5, 9}
+
+	for channels := 1; channels < len(optimalSizes); channels++ {
+		for _, config := range []Config{
+			{NewSet: outputset.NewPartitionedUnordered, PruningStrategy: SerialPruning},
+			{NewSet: outputset.NewPartitionedUnordered, PruningStrategy: ParallelPruning},
+			{NewSet: outputset.NewDense, PruningStrategy: ParallelPruning},
+		} {
+			config.Channels, config.Workers = channels, 4
+			engine, err := New(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := engine.Run(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Comparators != optimalSizes[channels] {
+				t.Errorf("expected %d comparators for %d channels, got %d", optimalSizes[channels], channels, result.Comparators)
+			}
+			if len(result.Networks) == 0 {
+				t.Errorf("expected at least one sorting network for %d channels", channels)
+			}
+			if len(result.Rounds) != result.Comparators {
+				t.Errorf("expected %d rounds, got %d", result.Comparators, len(result.Rounds))
+			}
+		}
+	}
+}
+
+func TestEngineRoundLimit(t *testing.T) {
+	engine, err := New(Config{Channels: 4, MaxRounds: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = engine.Run(context.Background()); err != ErrRoundLimit {
+		t.Errorf("expected round limit error, got %v", err)
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	if _, err := New(Config{Channels: 0}); err == nil {
+		t.Error("expected an error for 0 channels")
+	}
+}
+
+func TestEngineDepthMode(t *testing.T) {
+	optimalDepths := []int{0, 0, 1, 3, 3, 5, 5}
+
+	for channels := 1; channels < len(optimalDepths); channels++ {
+		for _, allLayers := range []bool{false, true} {
+			engine, err := New(Config{
+				Channels:  channels,
+				Mode:      DepthMode,
+				AllLayers: allLayers,
+				Workers:   4,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := engine.Run(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Depth != optimalDepths[channels] {
+				t.Errorf("expected depth %d for %d channels, got %d", optimalDepths[channels], channels, result.Depth)
+			}
+			for _, network := range result.Networks {
+				if depth := network.(*sortnet.ComparatorNetwork).Depth(); depth != result.Depth {
+					t.Errorf("expected a network of depth %d, got %d", result.Depth, depth)
+				}
+				if ok, _ := sortnet.IsSortingNetwork(network, channels); !ok {
+					t.Errorf("expected a sorting network for %d channels", channels)
+				}
+			}
+		}
+	}
+}
+
+func TestEngineReflection(t *testing.T) {
+	const channels = 6
+
+	for _, mode := range []Mode{SizeMode, DepthMode} {
+		var results [2]*Result
+		for i, reflection := range []bool{false, true} {
+			engine, err := New(Config{Channels: channels, Mode: mode, Reflection: reflection, Workers: 4})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if results[i], err = engine.Run(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		plain, reflected := results[0], results[1]
+		if plain.Comparators != reflected.Comparators || plain.Depth != reflected.Depth {
+			t.Errorf("mode %d: expected reflection to find networks of the same size and depth", mode)
+		}
+		for _, network := range reflected.Networks {
+			if ok, _ := sortnet.IsSortingNetwork(network, channels); !ok {
+				t.Errorf("mode %d: expected a sorting network, got\n%s", mode, network)
+			}
+		}
+
+		var fewer bool
+		for r := range plain.Rounds {
+			if reflected.Rounds[r].Remaining > plain.Rounds[r].Remaining {
+				t.Errorf("mode %d: round %d kept more networks with reflection", mode, r+1)
+			}
+			fewer = fewer || reflected.Rounds[r].Remaining < plain.Rounds[r].Remaining
+		}
+		if !fewer {
+			t.Errorf("mode %d: expected reflection to prune more networks", mode)
+		}
+	}
+}
+
+func TestEngineCertificate(t *testing.T) {
+	var buf bytes.Buffer
+	engine, err := New(Config{Channels: 5, Reflection: true, Workers: 4, Certificate: &buf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rounds, pruned, sorting int
+	r := cert.NewReader(&buf)
+	for {
+		record, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch record := record.(type) {
+		case *cert.Round:
+			rounds++
+			sorting = len(record.Sorting)
+		case *cert.Pruned:
+			pruned++
+			if len(record.Permutation) != 5 {
+				t.Errorf("expected a permutation of 5 channels, got %v", record.Permutation)
+			}
+		}
+	}
+
+	var expected int
+	for _, stats := range result.Rounds {
+		expected += stats.Pruned
+	}
+	if rounds != len(result.Rounds) || pruned != expected || sorting != len(result.Networks) {
+		t.Errorf("expected %d rounds, %d pruned and %d sorting networks, got %d, %d and %d",
+			len(result.Rounds), expected, len(result.Networks), rounds, pruned, sorting)
+	}
+
+	if _, err = New(Config{Channels: 5, Certificate: &buf, Resume: true, CheckpointPath: "x"}); err == nil {
+		t.Error("expected an error for a certificate when resuming")
+	}
+}
+
+func TestWitness(t *testing.T) {
+	const channels = 6
+
+	// the output sets of every network of two layers, which subsume each other in many ways
+	var sets []sortnet.OutputSet
+	empty := sortnet.PopulateOutputSet(outputset.NewPartitionedOrdered(channels), channels)
+	for _, first := range sortnet.Matchings(channels, false) {
+		for _, second := range sortnet.Matchings(channels, true) {
+			sets = append(sets, sortnet.DeriveFrom(empty, append(append([]sortnet.Comparator{}, first...), second...)...))
+		}
+		if len(sets) > 60 {
+			break
+		}
+	}
+
+	var subsumed int
+	for _, a := range sets {
+		for _, b := range sets {
+			permutation, ok := Witness(channels, a, b, sortnet.GeneratePermutationsBySubset)
+			if expected := Subsumes(channels, a, b, sortnet.TestedPermutations(sortnet.GeneratePermutationsByBitmap)); ok != expected {
+				t.Fatalf("expected the subset generator to find a permutation: %t, got %t", expected, ok)
+			}
+			if ok && !a.IsSubset(b, permutation) {
+				t.Fatalf("expected %v to make the set a subset", permutation)
+			}
+			if ok {
+				subsumed++
+			}
+		}
+	}
+	if subsumed <= len(sets) {
+		t.Errorf("expected more subsumptions than the %d sets subsuming themselves, got %d", len(sets), subsumed)
+	}
+}
+
+func TestPruneSets(t *testing.T) {
+	config := Config{Channels: 5, Reflection: true, PruningStrategy: SerialPruning, Workers: 2}
+	engine, _ := New(config)
+
+	s := &state{store: sortnet.NewNetworkStore(5), networks: []sortnet.NetworkID{sortnet.RootNetwork}}
+	s.sets = []sortnet.OutputSet{engine.config.NewSet(5)}
+	for round := 1; round <= 2; round++ {
+		children, err := engine.generate(context.Background(), round, s.store, s.networks, s.sets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.round, s.networks, s.sets, s.ids = round, children.networks, children.sets, children.ids
+	}
+
+	store, err := spill.New(t.TempDir(), engine.config.NewSet(5), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	for _, set := range s.sets {
+		if _, err = store.Append(set); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = engine.prune(context.Background(), s, func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	pruned, err := PruneSets(context.Background(), store, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pruned != s.stats.Pruned || pruned == 0 {
+		t.Errorf("expected %d pruned sets, got %d", s.stats.Pruned, pruned)
+	}
+	for id, set := range s.sets {
+		if stored, _ := store.Get(sortnet.NetworkID(id)); (stored == nil) != (set == nil) {
+			t.Errorf("expected set %d to be pruned from the store as well", id)
+		}
+	}
+}
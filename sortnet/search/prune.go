@@ -0,0 +1,249 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/cert"
+	"golang.org/x/sync/errgroup"
+)
+
+// prune removes every output set that is subsumed by another, by setting it to nil, starting with the sets subsumed
+// by s.pruneFrom. The pruned sets are counted in s.stats, and written to the certificate. checkpoint is called every
+// CheckpointEvery sets, and when the context is cancelled, such that the search can resume from s.pruneFrom.
+func (e *Engine) prune(ctx context.Context, s *state, checkpoint func() error) error {
+	if e.config.Pruner != nil {
+		if err := e.config.Pruner.Round(ctx, e.config.Channels, e.config.Reflection, s.sets); err != nil {
+			return err
+		}
+	}
+
+	var checked int
+	for ; s.pruneFrom < len(s.sets); s.pruneFrom++ {
+		currentID := s.pruneFrom
+		if s.sets[currentID] == nil {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			if saveErr := checkpoint(); saveErr != nil {
+				return saveErr
+			}
+			return err
+		}
+		if e.config.CheckpointEvery > 0 && checked > 0 && checked%e.config.CheckpointEvery == 0 {
+			if err := checkpoint(); err != nil {
+				return err
+			}
+		}
+		checked++
+
+		var subsumed []Subsumption
+		switch {
+		case e.config.Pruner != nil:
+			var err error
+			if subsumed, err = e.config.Pruner.Subsumed(ctx, currentID); err != nil {
+				return err
+			}
+		case e.config.PruningStrategy == SerialPruning:
+			subsumed, _ = e.pruneSerial(currentID, setSlice(s.sets), e.subsumers(s.sets[currentID]))
+		default:
+			subsumed = e.pruneParallel(ctx, currentID, s.sets, e.subsumers(s.sets[currentID]))
+		}
+
+		for _, sub := range subsumed {
+			s.sets[sub.ID] = nil
+			if s.cert == nil {
+				continue
+			}
+
+			err := s.cert.Pruned(cert.Pruned{
+				Round:       s.round,
+				ID:          s.ids[sub.ID],
+				By:          s.ids[currentID],
+				Permutation: sub.Permutation,
+				Reflected:   sub.Reflected,
+			})
+			if err != nil {
+				return fmt.Errorf("writing certificate: %w", err)
+			}
+		}
+		s.stats.Pruned += len(subsumed)
+	}
+
+	return nil
+}
+
+// Subsumption is a set subsumed by the set pruning with, and the witness of it: the permutation of the pruning set,
+// or of its reflection when Reflected is set, that is a subset of the subsumed set.
+type Subsumption struct {
+	ID          int
+	Permutation sortnet.PermutationMap
+	Reflected   bool
+}
+
+func subsumptionTest(a, b *sortnet.SetMetadata) bool {
+	return a.ST1(b) && a.ST2(b) && a.ST3(b)
+}
+
+// Subsumes reports whether a permutation of a is a subset of b. The metadata tests are tried first, as they rule out
+// most pairs without generating any permutation.
+func Subsumes(channels int, a, b sortnet.OutputSet, generate sortnet.GenerateSubsetPermutationsFunc) bool {
+	_, ok := Witness(channels, a, b, generate)
+	return ok
+}
+
+// Witness returns the permutation of a that is a subset of b, when a subsumes b.
+func Witness(channels int, a, b sortnet.OutputSet, generate sortnet.GenerateSubsetPermutationsFunc) (sortnet.PermutationMap, bool) {
+	if !subsumptionTest(a.Metadata(), b.Metadata()) {
+		return nil, false
+	}
+
+	var witness sortnet.PermutationMap
+	ok := generate(channels, a, b, func(permutationMap sortnet.PermutationMap) bool {
+		// generators may reuse the map for the next permutation
+		witness = append(sortnet.PermutationMap{}, permutationMap...)
+		return true
+	})
+
+	return witness, ok
+}
+
+// Sets is a round of output sets addressed by their position. Each skips the sets that were removed, eg. when
+// pruned. It is implemented by *spill.Store for rounds that do not fit in memory.
+type Sets interface {
+	Len() int
+	Get(id sortnet.NetworkID) (sortnet.OutputSet, error)
+	Remove(id sortnet.NetworkID) error
+	Each(fn func(id sortnet.NetworkID, set sortnet.OutputSet) bool) error
+}
+
+// PruneSets removes every set that is subsumed by another from the sets, as the engine does with SerialPruning, and
+// returns the number of removed sets. The channels, reflection and permutation generator are taken from the config.
+func PruneSets(ctx context.Context, sets Sets, config Config) (int, error) {
+	config.setDefaults()
+	if err := config.validate(); err != nil {
+		return 0, err
+	}
+	e := &Engine{config: config}
+
+	var pruned int
+	for currentID := 0; currentID < sets.Len(); currentID++ {
+		if err := ctx.Err(); err != nil {
+			return pruned, err
+		}
+
+		current, err := sets.Get(sortnet.NetworkID(currentID))
+		if err != nil {
+			return pruned, err
+		}
+		if current == nil {
+			continue
+		}
+
+		subsumed, err := e.pruneSerial(currentID, sets, e.subsumers(current))
+		if err != nil {
+			return pruned, err
+		}
+		for _, sub := range subsumed {
+			if err = sets.Remove(sortnet.NetworkID(sub.ID)); err != nil {
+				return pruned, err
+			}
+		}
+		pruned += len(subsumed)
+	}
+
+	return pruned, nil
+}
+
+// iterableSets is the part of Sets read by the pruning loops.
+type iterableSets interface {
+	Each(fn func(id sortnet.NetworkID, set sortnet.OutputSet) bool) error
+}
+
+// setSlice lets the pruning loops read the sets of the engine, where pruned sets are nil.
+type setSlice []sortnet.OutputSet
+
+func (s setSlice) Each(fn func(id sortnet.NetworkID, set sortnet.OutputSet) bool) error {
+	for id, set := range s {
+		if set != nil && !fn(sortnet.NetworkID(id), set) {
+			break
+		}
+	}
+	return nil
+}
+
+// subsumers returns the sets tested against the other sets of the round on behalf of the set: the set itself, and its
+// reflection when Reflection is set.
+func (e *Engine) subsumers(set sortnet.OutputSet) []sortnet.OutputSet {
+	if !e.config.Reflection {
+		return []sortnet.OutputSet{set}
+	}
+
+	return []sortnet.OutputSet{set, sortnet.ReflectOutputSet(set, e.config.Channels)}
+}
+
+// subsumes tests whether any of the subsumers subsumes the set with the given id.
+func (e *Engine) subsumes(subsumers []sortnet.OutputSet, id int, set sortnet.OutputSet) (Subsumption, bool) {
+	for i, subsumer := range subsumers {
+		if permutation, ok := Witness(e.config.Channels, subsumer, set, e.config.GeneratePermutations); ok {
+			return Subsumption{ID: id, Permutation: permutation, Reflected: i > 0}, true
+		}
+	}
+
+	return Subsumption{}, false
+}
+
+// pruneSerial returns the sets subsumed by the subsumers of the set currentID, reading the sets in a single pass.
+func (e *Engine) pruneSerial(currentID int, sets iterableSets, subsumers []sortnet.OutputSet) ([]Subsumption, error) {
+	var subsumed []Subsumption
+	err := sets.Each(func(id sortnet.NetworkID, target sortnet.OutputSet) bool {
+		if int(id) != currentID {
+			if sub, ok := e.subsumes(subsumers, int(id), target); ok {
+				subsumed = append(subsumed, sub)
+			}
+		}
+		return true
+	})
+
+	return subsumed, err
+}
+
+func (e *Engine) pruneParallel(ctx context.Context, currentID int, sets []sortnet.OutputSet, subsumers []sortnet.OutputSet) []Subsumption {
+	g, _ := errgroup.WithContext(ctx)
+	work := make(chan int, e.config.Workers)
+
+	g.Go(func() error {
+		defer close(work)
+		for id, target := range sets {
+			if currentID == id || target == nil {
+				continue
+			}
+
+			work <- id
+		}
+		return nil
+	})
+
+	subsumed := make([][]Subsumption, e.config.Workers)
+	for w := 0; w < e.config.Workers; w++ {
+		w := w
+		g.Go(func() error {
+			for id := range work {
+				if sub, ok := e.subsumes(subsumers, id, sets[id]); ok {
+					subsumed[w] = append(subsumed[w], sub)
+				}
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var all []Subsumption
+	for w := range subsumed {
+		all = append(all, subsumed[w]...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	return all
+}
@@ -0,0 +1,41 @@
+package search
+
+import (
+	"time"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+// Result of a completed search.
+type Result struct {
+	// Networks holds every sorting network discovered in the final round.
+	Networks []sortnet.Network
+
+	// Comparators is the number of comparators in each of the discovered networks. Only set in SizeMode.
+	Comparators int
+
+	// Depth is the number of layers in each of the discovered networks. Only set in DepthMode.
+	Depth int
+
+	Rounds []RoundStats
+}
+
+// RoundStats describes the work done in a single round, where round k derives networks of k comparators, or of k
+// layers in DepthMode.
+type RoundStats struct {
+	Round int
+
+	// Generated is the number of networks derived from the previous round.
+	Generated int
+
+	// Redundant is the number of generated networks whose last comparator or layer did not change the output set.
+	Redundant int
+
+	// Pruned is the number of networks removed by subsumption.
+	Pruned int
+
+	// Remaining is the number of networks passed on to the next round.
+	Remaining int
+
+	Duration time.Duration
+}
@@ -0,0 +1,22 @@
+//go:build !sortnet32 && !sortnet64
+
+package sortnet
+
+import "math/bits"
+
+// BinarySequence is 16 bits wide by default, which keeps output sets compact. Build with the sortnet32 or
+// sortnet64 tag to support networks with more channels.
+type BinarySequence uint16
+
+const MaxChannels = 16
+const MaxChannelsMask = 0xffff
+
+func (b BinarySequence) OnesCount() int {
+	return bits.OnesCount16(uint16(b))
+}
+
+// MostSignificantBitOffset returns offset for most significant set bit, -1 when there are no set bits.
+func (b BinarySequence) MostSignificantBitOffset() int {
+	width := bits.Len16(uint16(b))
+	return width - 1
+}
@@ -0,0 +1,21 @@
+//go:build sortnet32 && !sortnet64
+
+package sortnet
+
+import "math/bits"
+
+// BinarySequence is 32 bits wide when built with the sortnet32 tag.
+type BinarySequence uint32
+
+const MaxChannels = 32
+const MaxChannelsMask = 0xffff_ffff
+
+func (b BinarySequence) OnesCount() int {
+	return bits.OnesCount32(uint32(b))
+}
+
+// MostSignificantBitOffset returns offset for most significant set bit, -1 when there are no set bits.
+func (b BinarySequence) MostSignificantBitOffset() int {
+	width := bits.Len32(uint32(b))
+	return width - 1
+}
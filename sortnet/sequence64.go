@@ -0,0 +1,21 @@
+//go:build sortnet64
+
+package sortnet
+
+import "math/bits"
+
+// BinarySequence is 64 bits wide when built with the sortnet64 tag.
+type BinarySequence uint64
+
+const MaxChannels = 64
+const MaxChannelsMask = 0xffff_ffff_ffff_ffff
+
+func (b BinarySequence) OnesCount() int {
+	return bits.OnesCount64(uint64(b))
+}
+
+// MostSignificantBitOffset returns offset for most significant set bit, -1 when there are no set bits.
+func (b BinarySequence) MostSignificantBitOffset() int {
+	width := bits.Len64(uint64(b))
+	return width - 1
+}
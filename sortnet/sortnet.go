@@ -1,23 +1,7 @@
 package sortnet
 
-import "math/bits"
-
-const MaxChannels = 16
-const MaxChannelsMask = 0b1111_1111_1111_1111
 const None = 0
 
-type BinarySequence uint16
-
-func (b BinarySequence) OnesCount() int {
-	return bits.OnesCount16(uint16(b))
-}
-
-// MostSignificantBitOffset returns offset for most significant set bit, -1 when there are no set bits.
-func (b BinarySequence) MostSignificantBitOffset() int {
-	width := bits.Len16(uint16(b))
-	return width - 1
-}
-
 func SequenceMask(channels int) BinarySequence {
 	return BinarySequence(MaxChannelsMask >> (MaxChannels - channels))
 }
@@ -0,0 +1,36 @@
+package sortnet
+
+import "testing"
+
+func TestSequenceMask(t *testing.T) {
+	if mask := SequenceMask(MaxChannels); mask != MaxChannelsMask {
+		t.Errorf("expected mask of all channels to be %b, got %b", BinarySequence(MaxChannelsMask), mask)
+	}
+	if mask := SequenceMask(3); mask != 0b111 {
+		t.Errorf("expected mask of 3 channels to be 0b111, got %b", mask)
+	}
+}
+
+func TestWidestChannel(t *testing.T) {
+	highest := MaxChannels - 1
+	network := &ComparatorNetwork{
+		comparators: []Comparator{{From: highest, To: 0}},
+	}
+
+	seq := BinarySequence(1) << highest
+	if output := network.Transform(seq); output != 0b1 {
+		t.Errorf("expected the highest channel to be moved to channel 0, got %b", output)
+	}
+
+	if offset := seq.MostSignificantBitOffset(); offset != highest {
+		t.Errorf("expected most significant bit offset %d, got %d", highest, offset)
+	}
+
+	var visited int
+	for it := NewSequenceIterator(SequenceMask(MaxChannels)); !it.Empty(); it.Next() {
+		visited++
+	}
+	if visited != MaxChannels {
+		t.Errorf("expected to iterate %d offsets, got %d", MaxChannels, visited)
+	}
+}
@@ -0,0 +1,287 @@
+// Package spill keeps the output sets of a search round on disk, for rounds with more output sets than fit in memory.
+// A Store writes every set to a local file as it is appended and keeps a bounded number of them in memory. Sets are
+// addressed by their NetworkID, the position they were appended at, such that a pruning loop can use the store as it
+// would use a slice of output sets:
+//
+//	for id := sortnet.NetworkID(0); int(id) < store.Len(); id++ {
+//		set, err := store.Get(id)
+//		...
+//		err = store.Remove(subsumedID)
+//		...
+//	}
+//
+// search.PruneSets prunes a round held in a Store.
+package spill
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+)
+
+var ErrClosed = errors.New("spill store is closed")
+
+// Store is an append-only list of output sets backed by a temporary file. It is safe for concurrent use, although
+// appending is expected to finish before the sets are read by several goroutines.
+type Store struct {
+	mu    sync.Mutex
+	empty sortnet.OutputSet
+	file  *os.File
+	w     *bufio.Writer
+
+	// offsets holds the file offset of every set, and size is the offset of the next one. The sets before flushed
+	// can be read from the file.
+	offsets []int64
+	size    int64
+	flushed int64
+	removed []uint64
+	count   int
+
+	// cached holds at most limit sets in memory, which are evicted in the order they were cached.
+	limit  int
+	cached map[sortnet.NetworkID]sortnet.OutputSet
+	queue  []sortnet.NetworkID
+}
+
+// New creates a store with its file in dir, or the default temporary directory when dir is empty. The sets are
+// decoded into sets created by empty.NewEmpty, and at most limit sets are kept in memory.
+func New(dir string, empty sortnet.OutputSet, limit int) (*Store, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("limit must not be negative, got %d", limit)
+	}
+
+	file, err := os.CreateTemp(dir, "sortnet-spill-*")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{
+		empty:  empty.NewEmpty(),
+		file:   file,
+		w:      bufio.NewWriter(file),
+		limit:  limit,
+		cached: make(map[sortnet.NetworkID]sortnet.OutputSet, limit),
+	}, nil
+}
+
+// Append writes the set to the store and returns its ID.
+func (s *Store) Append(set sortnet.OutputSet) (sortnet.NetworkID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return 0, ErrClosed
+	}
+
+	data, err := set.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	var header [4]byte
+	binary.LittleEndian.PutUint32(header[:], uint32(len(data)))
+	if _, err = s.w.Write(header[:]); err != nil {
+		return 0, err
+	}
+	if _, err = s.w.Write(data); err != nil {
+		return 0, err
+	}
+
+	id := sortnet.NetworkID(len(s.offsets))
+	s.offsets = append(s.offsets, s.size)
+	s.size += int64(len(header) + len(data))
+	if len(s.offsets) > len(s.removed)*64 {
+		s.removed = append(s.removed, 0)
+	}
+	s.count++
+	s.cache(id, set)
+
+	return id, nil
+}
+
+// Len returns the number of appended sets, including the removed ones. IDs range from 0 to Len-1.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.offsets)
+}
+
+// Count returns the number of sets that have not been removed.
+func (s *Store) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.count
+}
+
+// Remove marks the set as removed, such as when it is pruned. Get then returns nil for it.
+func (s *Store) Remove(id sortnet.NetworkID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.check(id); err != nil {
+		return err
+	}
+	if s.isRemoved(id) {
+		return nil
+	}
+	s.removed[id/64] |= 1 << (id % 64)
+	s.count--
+	delete(s.cached, id)
+	return nil
+}
+
+// check returns an error unless the store is open and holds the ID.
+func (s *Store) check(id sortnet.NetworkID) error {
+	if s.file == nil {
+		return ErrClosed
+	}
+	if id < 0 || int(id) >= len(s.offsets) {
+		return fmt.Errorf("output set %d is not in the store of %d sets", id, len(s.offsets))
+	}
+	return nil
+}
+
+// isRemoved reports whether the set was removed. The ID must be checked first.
+func (s *Store) isRemoved(id sortnet.NetworkID) bool {
+	return s.removed[id/64]&(1<<(id%64)) != 0
+}
+
+// Get returns the set of the ID, or nil when it was removed. Sets that are not in memory are read from the file and
+// replace the oldest cached set.
+func (s *Store) Get(id sortnet.NetworkID) (sortnet.OutputSet, error) {
+	s.mu.Lock()
+	if err := s.check(id); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	if s.isRemoved(id) {
+		s.mu.Unlock()
+		return nil, nil
+	}
+	if set, ok := s.cached[id]; ok {
+		s.mu.Unlock()
+		return set, nil
+	}
+	if err := s.flush(); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	offset, file, size := s.offsets[id], s.file, s.flushed
+	s.mu.Unlock()
+
+	set, err := s.read(bufio.NewReader(io.NewSectionReader(file, offset, size-offset)))
+	if err != nil {
+		return nil, fmt.Errorf("reading output set %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	s.cache(id, set)
+	s.mu.Unlock()
+	return set, nil
+}
+
+// Each calls fn with every set that has not been removed, in the order of their IDs, until fn returns false. The
+// sets are read sequentially from the file and bypass the cache.
+func (s *Store) Each(fn func(id sortnet.NetworkID, set sortnet.OutputSet) bool) error {
+	s.mu.Lock()
+	if s.file == nil {
+		s.mu.Unlock()
+		return ErrClosed
+	}
+	if err := s.flush(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	n, file, size := len(s.offsets), s.file, s.flushed
+	s.mu.Unlock()
+
+	r := bufio.NewReader(io.NewSectionReader(file, 0, size))
+	for id := sortnet.NetworkID(0); int(id) < n; id++ {
+		set, err := s.read(r)
+		if err != nil {
+			return fmt.Errorf("reading output set %d: %w", id, err)
+		}
+
+		s.mu.Lock()
+		removed := s.isRemoved(id)
+		s.mu.Unlock()
+		if removed {
+			continue
+		}
+		if !fn(id, set) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// Close removes the file of the store.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return ErrClosed
+	}
+
+	err := s.file.Close()
+	if removeErr := os.Remove(s.file.Name()); err == nil {
+		err = removeErr
+	}
+	s.file, s.cached, s.queue = nil, nil, nil
+	return err
+}
+
+func (s *Store) flush() error {
+	if s.flushed == s.size {
+		return nil
+	}
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+
+	s.flushed = s.size
+	return nil
+}
+
+func (s *Store) cache(id sortnet.NetworkID, set sortnet.OutputSet) {
+	if s.limit == 0 {
+		return
+	}
+
+	// the queue may hold removed sets, which are dropped as if they were evicted
+	for len(s.queue) >= s.limit {
+		delete(s.cached, s.queue[0])
+		s.queue = s.queue[1:]
+	}
+	s.cached[id] = set
+	s.queue = append(s.queue, id)
+}
+
+// read decodes the next set of the reader, stored as the length of its binary encoding followed by the encoding.
+func (s *Store) read(r io.Reader) (sortnet.OutputSet, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, binary.LittleEndian.Uint32(header[:]))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	set := s.empty.NewEmpty()
+	if err := set.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
@@ -0,0 +1,89 @@
+package spill
+
+import (
+	"testing"
+
+	"github.com/andersfylling/go-sortnet/sortnet"
+	"github.com/andersfylling/go-sortnet/sortnet/outputset"
+)
+
+func TestStore(t *testing.T) {
+	const channels = 6
+	all := outputset.NewPartitionedOrdered(channels)
+
+	var sets []sortnet.OutputSet
+	network := sortnet.NewBatcherOddEvenMergeSort(channels)
+	for i := 0; i <= network.Len(); i++ {
+		prefix, _ := sortnet.NewComparatorNetwork(channels, network.Comparators()[:i]...)
+		sets = append(sets, all.Derive(prefix))
+	}
+
+	store, err := New(t.TempDir(), all, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	for i, set := range sets {
+		id, err := store.Append(set)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int(id) != i {
+			t.Fatalf("expected id %d, got %d", i, id)
+		}
+	}
+	for _, id := range []sortnet.NetworkID{1, 4} {
+		if err = store.Remove(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = store.Remove(sortnet.NetworkID(len(sets))); err == nil {
+		t.Error("expected an error when removing a set that is not in the store")
+	}
+
+	if store.Len() != len(sets) || store.Count() != len(sets)-2 {
+		t.Errorf("expected %d sets of which %d remain, got %d and %d", len(sets), len(sets)-2, store.Len(), store.Count())
+	}
+
+	same := func(a, b sortnet.OutputSet) bool {
+		return a.Size() == b.Size() && a.IsSubset(b, nil)
+	}
+
+	// reversed, such that most sets are read from the file rather than the cache
+	for id := sortnet.NetworkID(store.Len() - 1); id >= 0; id-- {
+		set, err := store.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case id == 1 || id == 4:
+			if set != nil {
+				t.Errorf("expected set %d to be removed", id)
+			}
+		case set == nil || !same(set, sets[id]):
+			t.Errorf("set %d differs from the appended set", id)
+		}
+	}
+
+	var scanned int
+	err = store.Each(func(id sortnet.NetworkID, set sortnet.OutputSet) bool {
+		if id == 1 || id == 4 {
+			t.Errorf("expected set %d to be skipped", id)
+		}
+		if !same(set, sets[id]) {
+			t.Errorf("set %d differs from the appended set", id)
+		}
+		scanned++
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scanned != store.Count() {
+		t.Errorf("expected to scan %d sets, got %d", store.Count(), scanned)
+	}
+	if len(store.cached) > 3 {
+		t.Errorf("expected at most 3 cached sets, got %d", len(store.cached))
+	}
+}
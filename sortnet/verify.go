@@ -0,0 +1,47 @@
+package sortnet
+
+import "fmt"
+
+// Counterexample is an input a network fails to sort, together with the unsorted output it produces.
+type Counterexample struct {
+	Input  BinarySequence
+	Output BinarySequence
+}
+
+// IsSorted reports whether every set bit is below every unset bit, which is the sorted order produced by a
+// comparator network. See ComparatorNetwork.Transform.
+func (b BinarySequence) IsSorted() bool {
+	return b&(b+1) == 0
+}
+
+// IsSortingNetwork verifies the network using the 0-1 principle: a comparator network sorts every input if it sorts
+// every binary input. All 2^channels binary sequences are checked, and the first one that is not sorted is returned
+// as a counterexample.
+//
+// The channels must be in the range [0, MaxChannels] and cover every channel of the network, as for a network created
+// by NewComparatorNetwork with the same channels. This is a precondition rather than an error, and the function panics
+// when it does not hold, so channels read from user input must be validated first.
+func IsSortingNetwork(network Network, channels int) (bool, *Counterexample) {
+	if channels < 0 || channels > MaxChannels {
+		panic(fmt.Sprintf("channels must be in the range [0, %d], got %d", MaxChannels, channels))
+	}
+	if n, ok := network.(interface{ Channels() int }); ok && n.Channels() > channels {
+		panic(fmt.Sprintf("network has %d channels, but only %d are checked", n.Channels(), channels))
+	}
+
+	mask := SequenceMask(channels)
+	for input := BinarySequence(0); ; input++ {
+		if output := network.Transform(input); !output.IsSorted() {
+			return false, &Counterexample{
+				Input:  input,
+				Output: output,
+			}
+		}
+
+		if input == mask {
+			break
+		}
+	}
+
+	return true, nil
+}
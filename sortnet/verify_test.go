@@ -0,0 +1,38 @@
+package sortnet
+
+import "testing"
+
+func TestIsSortingNetwork(t *testing.T) {
+	network := &ComparatorNetwork{
+		comparators: []Comparator{
+			{From: 1, To: 0}, {From: 3, To: 2}, {From: 2, To: 0}, {From: 3, To: 1},
+		},
+	}
+
+	ok, counterexample := IsSortingNetwork(network, 4)
+	if ok {
+		t.Fatal("expected the network to not sort every input")
+	}
+	if output := network.Transform(counterexample.Input); output != counterexample.Output || output.IsSorted() {
+		t.Errorf("invalid counterexample %+v", counterexample)
+	}
+
+	network.comparators = append(network.comparators, Comparator{From: 2, To: 1})
+	if ok, counterexample = IsSortingNetwork(network, 4); !ok {
+		t.Errorf("expected a sorting network, got counterexample %+v", counterexample)
+	}
+}
+
+func TestIsSortingNetworkChannels(t *testing.T) {
+	network := NewBatcherOddEvenMergeSort(4)
+	for _, channels := range []int{-1, 3, MaxChannels + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic when checking %d channels", channels)
+				}
+			}()
+			IsSortingNetwork(network, channels)
+		}()
+	}
+}